@@ -1,6 +1,8 @@
 package conf
 
 import (
+	"time"
+
 	"github.com/spf13/viper"
 )
 
@@ -10,7 +12,18 @@ type IConfiguration interface {
 	LoadConfiguration(basePath string) (IConfiguration, error)
 	GetPgsqlConnectionString() (string, error)
     GetGormConnectionString() (string, error)
+    GetPoolSettings() (PoolSettings, error)
 	GetServerAddress() (string, error)
+	GetQueryTimeout(endpoint string) (time.Duration, error)
+	GetRequireIfMatch() (bool, error)
+}
+
+// Параметры пула соединений с базой данных
+type PoolSettings struct {
+    MaxOpenConns    int
+    MaxIdleConns    int
+    ConnMaxLifetime time.Duration
+    ConnMaxIdleTime time.Duration
 }
 
 type Configuration struct {
@@ -54,9 +67,52 @@ func (config Configuration) GetGormConnectionString() (string, error) {
     return pgsqlConn, nil
 }
 
+func (config Configuration) GetPoolSettings() (PoolSettings, error) {
+    var maxopen = "dbconnection.pool.max_open_conns"
+    var maxidle = "dbconnection.pool.max_idle_conns"
+    var lifetime = "dbconnection.pool.conn_max_lifetime"
+    var idletime = "dbconnection.pool.conn_max_idle_time"
+
+    config.rt_viper.BindEnv(maxopen)
+    config.rt_viper.BindEnv(maxidle)
+    config.rt_viper.BindEnv(lifetime)
+    config.rt_viper.BindEnv(idletime)
+
+    settings := PoolSettings{
+        MaxOpenConns:    config.rt_viper.GetInt(maxopen),
+        MaxIdleConns:    config.rt_viper.GetInt(maxidle),
+        ConnMaxLifetime: config.rt_viper.GetDuration(lifetime),
+        ConnMaxIdleTime: config.rt_viper.GetDuration(idletime),
+    }
+
+    return settings, nil
+}
+
 func (config Configuration) GetServerAddress() (string, error) {
     var svraddr = "server.addr"
     config.rt_viper.BindEnv(svraddr)
     svrAddress := config.rt_viper.GetString("server.addr")
     return svrAddress, nil
-}
\ No newline at end of file
+}
+
+// GetQueryTimeout возвращает таймаут запросов для группы методов API,
+// если он не задан - общий таймаут server.query_timeout.default
+func (config Configuration) GetQueryTimeout(endpoint string) (time.Duration, error) {
+    var svrtimeout = "server.query_timeout." + endpoint
+    var deftimeout = "server.query_timeout.default"
+    config.rt_viper.BindEnv(svrtimeout)
+    config.rt_viper.BindEnv(deftimeout)
+
+    if config.rt_viper.IsSet(svrtimeout) {
+        return config.rt_viper.GetDuration(svrtimeout), nil
+    }
+
+    return config.rt_viper.GetDuration(deftimeout), nil
+}
+
+// GetRequireIfMatch возвращает признак обязательного заголовка If-Match при изменении и удалении
+func (config Configuration) GetRequireIfMatch() (bool, error) {
+    var requireIfMatch = "server.require_if_match"
+    config.rt_viper.BindEnv(requireIfMatch)
+    return config.rt_viper.GetBool(requireIfMatch), nil
+}
@@ -39,4 +39,65 @@ func TestGetPgsqlConnectionString(t *testing.T) {
 	log.Printf("Строка подключения: '%v'", connection)
 
 }
-    
\ No newline at end of file
+    
+func TestGetPoolSettings(t *testing.T) {
+    
+    // создать экземпляр конфигурации
+    config, err := Configuration{}.New().LoadConfiguration("./../..");
+
+    if err != nil {
+        t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+    }
+
+	settings, err := config.GetPoolSettings();
+
+    if err != nil {
+        t.Errorf("Не удалось получить параметры пула соединений: %v", err)
+    }
+
+    if settings.MaxOpenConns <= 0 || settings.ConnMaxLifetime <= 0 {
+        t.Errorf("Параметры пула соединений не заданы: %+v", settings)
+    }
+
+	log.Printf("Параметры пула соединений: '%+v'", settings)
+
+}
+
+func TestGetQueryTimeout(t *testing.T) {
+    
+    // создать экземпляр конфигурации
+    config, err := Configuration{}.New().LoadConfiguration("./../..");
+
+    if err != nil {
+        t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+    }
+
+	deftimeout, _ := config.GetQueryTimeout("default");
+	unknown, _ := config.GetQueryTimeout("unknown");
+
+    if unknown != deftimeout {
+        t.Errorf("Для неизвестного метода ожидался общий таймаут %v, получено %v", deftimeout, unknown)
+    }
+
+	flights, _ := config.GetQueryTimeout("flights");
+
+	log.Printf("Таймауты запросов: общий '%v', рейсы '%v'", deftimeout, flights)
+
+}
+
+func TestGetRequireIfMatch(t *testing.T) {
+
+    // создать экземпляр конфигурации
+    config, err := Configuration{}.New().LoadConfiguration("./../..");
+
+    if err != nil {
+        t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+    }
+
+	required, _ := config.GetRequireIfMatch();
+
+    if required {
+        t.Errorf("По умолчанию заголовок If-Match не должен быть обязательным")
+    }
+
+}
@@ -0,0 +1,150 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Вид ошибки сервиса
+type ErrorKind int
+
+const (
+	ErrorInternal ErrorKind = iota
+	ErrorNotFound
+	ErrorConflict
+	ErrorValidation
+	ErrorUnavailable
+	ErrorArgument
+	ErrorTimeout
+	ErrorReference
+	ErrorPrecondition
+	ErrorPreconditionRequired
+)
+
+func (kind ErrorKind) String() string {
+	switch kind {
+	case ErrorNotFound:
+		return "not_found"
+	case ErrorConflict:
+		return "conflict"
+	case ErrorValidation:
+		return "validation"
+	case ErrorUnavailable:
+		return "unavailable"
+	case ErrorArgument:
+		return "argument"
+	case ErrorTimeout:
+		return "timeout"
+	case ErrorReference:
+		return "reference"
+	case ErrorPrecondition:
+		return "precondition"
+	case ErrorPreconditionRequired:
+		return "precondition_required"
+	default:
+		return "internal"
+	}
+}
+
+// Типизированная ошибка сервиса. Key - ключ сообщения в каталоге i18n, Args - его параметры,
+// Message - сообщение на языке по умолчанию для журнала.
+type ServiceError struct {
+	Kind        ErrorKind
+	Key         string
+	Args        []any
+	Message     string
+	Validations []model.Validation
+	Err         error
+}
+
+func newServiceError(kind ErrorKind, err error, key string, args ...any) *ServiceError {
+	return &ServiceError{Kind: kind, Key: key, Args: args, Message: i18n.Text(nil, key, args...), Err: err}
+}
+
+func (e *ServiceError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	}
+	return e.Message
+}
+
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// NewNotFoundError - объект не найден
+func NewNotFoundError(key string, args ...any) error {
+	return newServiceError(ErrorNotFound, nil, key, args...)
+}
+
+// NewConflictError - объект уже существует или используется
+func NewConflictError(key string, args ...any) error {
+	return newServiceError(ErrorConflict, nil, key, args...)
+}
+
+// NewInUseError - объект используется другими записями, dependencies перечисляет зависимые записи
+func NewInUseError(key string, dependencies []model.Validation, args ...any) error {
+	serr := newServiceError(ErrorConflict, nil, key, args...)
+	serr.Validations = dependencies
+	return serr
+}
+
+// NewValidationError - ошибки входных данных
+func NewValidationError(key string, validations ...model.Validation) error {
+	serr := newServiceError(ErrorValidation, nil, key)
+	serr.Validations = validations
+	return serr
+}
+
+// NewArgumentError - неверные аргументы запроса
+func NewArgumentError(key string, err error, args ...any) error {
+	return newServiceError(ErrorArgument, err, key, args...)
+}
+
+// NewUnavailableError - база данных или другой ресурс недоступен
+func NewUnavailableError(key string, err error) error {
+	return newServiceError(ErrorUnavailable, err, key)
+}
+
+// NewTimeoutError - превышено время выполнения запроса
+func NewTimeoutError(key string, err error) error {
+	return newServiceError(ErrorTimeout, err, key)
+}
+
+// NewReferenceError - запись ссылается на несуществующий объект или на нее ссылаются другие записи.
+// Текст ошибки драйвера содержит таблицы и ограничения базы данных и в ответ не передается.
+func NewReferenceError(key string, args ...any) error {
+	return newServiceError(ErrorReference, nil, key, args...)
+}
+
+// NewPreconditionError - версия объекта не совпадает с условием If-Match
+func NewPreconditionError(key string, args ...any) error {
+	return newServiceError(ErrorPrecondition, nil, key, args...)
+}
+
+// NewPreconditionRequiredError - изменение без условия If-Match запрещено
+func NewPreconditionRequiredError(key string, args ...any) error {
+	return newServiceError(ErrorPreconditionRequired, nil, key, args...)
+}
+
+// NewInternalError - прочие ошибки
+func NewInternalError(key string, err error, args ...any) error {
+	return newServiceError(ErrorInternal, err, key, args...)
+}
+
+// AsServiceError приводит ошибку к ServiceError, неизвестные ошибки считаются внутренними
+func AsServiceError(err error) *ServiceError {
+	var serr *ServiceError
+	if errors.As(err, &serr) {
+		return serr
+	}
+	return newServiceError(ErrorInternal, err, i18n.MsgErrorInternal)
+}
+
+// ErrorKindOf возвращает вид ошибки
+func ErrorKindOf(err error) ErrorKind {
+	return AsServiceError(err).Kind
+}
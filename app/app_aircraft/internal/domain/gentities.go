@@ -4,16 +4,9 @@ import (
 	"github.com/jackc/pgx/pgtype"
 ) 
 
-// Названия типа JSONB
-type NameLang struct {
-    NameRu string `json:"ru"`
-    NameEn string `json:"en"`
-}
-
-
 type GAircraft struct {
 	Code     string  `gorm:"primaryKey;column:aircraft_code;not null"`
-	JNames   pgtype.JSONB `gorm:"type:jsonb;default:'{}';column:model;not null"`
+	JNames   LocalizedText `gorm:"type:jsonb;default:'{}';column:model;not null"`
 	Range  	 int 	 `gorm:"column:range;not null"`
 }
 
@@ -24,8 +17,8 @@ func (GAircraft) TableName() string {
 
 type GAirport struct {
 	Code       string  `gorm:"primaryKey;column:airport_code;not null"`
-	JNames     pgtype.JSONB `gorm:"type:jsonb;default:'{}';column:airport_name;not null"`
-	JCityNames pgtype.JSONB `gorm:"type:jsonb;default:'{}';column:city;not null"`
+	JNames     LocalizedText `gorm:"type:jsonb;default:'{}';column:airport_name;not null"`
+	JCityNames LocalizedText `gorm:"type:jsonb;default:'{}';column:city;not null"`
 	Position   Point `gorm:"type:point;column:coordinates;not null"` 
 	Timezone   string `gorm:"column:timezone;not null"`
 
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// Текст на нескольких языках: код языка (ru, en, de, zh...) -> значение.
+// Хранится в JSONB, поэтому новый язык не требует изменения схемы или структур.
+type LocalizedText map[string]string
+
+// Implement the Valuer interface for saving to the database
+func (t LocalizedText) Value() (driver.Value, error) {
+	if t == nil {
+		return "{}", nil
+	}
+
+	data, err := json.Marshal(map[string]string(t))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(data), nil
+}
+
+// Implement the Scanner interface for reading from the database.
+// NULL читается как пустой текст.
+func (t *LocalizedText) Scan(value interface{}) error {
+	var data []byte
+
+	switch v := value.(type) {
+	case nil:
+		*t = LocalizedText{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("could not scan type %T into LocalizedText", value)
+	}
+
+	text := LocalizedText{}
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("invalid localized text: %w", err)
+	}
+
+	*t = text
+	return nil
+}
+
+// GormDataType возвращает тип колонки для gorm
+func (LocalizedText) GormDataType() string {
+	return "jsonb"
+}
+
+// Get возвращает значение на языке lang или пустую строку
+func (t LocalizedText) Get(lang string) string {
+	return t[lang]
+}
+
+// With возвращает копию текста со значением на языке lang, пустое значение не добавляется
+func (t LocalizedText) With(lang string, value string) LocalizedText {
+	text := maps.Clone(t)
+	if text == nil {
+		text = LocalizedText{}
+	}
+	if value != "" {
+		text[lang] = value
+	}
+	return text
+}
+
+// Languages возвращает текст как карту языков для модели ответа
+func (t LocalizedText) Languages() map[string]string {
+	return maps.Clone(map[string]string(t))
+}
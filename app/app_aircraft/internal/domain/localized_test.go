@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestLocalizedTextScan тестирует чтение текста на нескольких языках из JSONB
+func TestLocalizedTextScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected LocalizedText
+		valid    bool
+	}{
+		{"Scan_String", `{"ru": "Москва", "en": "Moscow"}`, LocalizedText{"ru": "Москва", "en": "Moscow"}, true},
+		{"Scan_Bytes", []byte(`{"ru": "Москва", "de": "Moskau", "zh": "莫斯科"}`),
+			LocalizedText{"ru": "Москва", "de": "Moskau", "zh": "莫斯科"}, true},
+		{"Scan_Null", nil, LocalizedText{}, true},
+		{"Scan_Type", 42, nil, false},
+		{"Scan_Json", `["Moscow"]`, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var text LocalizedText
+
+			err := text.Scan(tt.value)
+			if (err == nil) != tt.valid {
+				t.Fatalf("Ожидалась корректность %v, получена ошибка %v", tt.valid, err)
+			}
+			if tt.valid && !reflect.DeepEqual(text, tt.expected) {
+				t.Errorf("Ожидалось %v, получено %v", tt.expected, text)
+			}
+		})
+	}
+}
+
+// TestLocalizedTextValue тестирует запись и изменение текста
+func TestLocalizedTextValue(t *testing.T) {
+	var empty LocalizedText
+	if value, _ := empty.Value(); value != "{}" {
+		t.Errorf("Ожидался пустой объект, получено %v", value)
+	}
+
+	text := LocalizedText{"ru": "Москва"}.With("en", "Moscow").With("de", "")
+	value, err := text.Value()
+	if err != nil || value != `{"en":"Moscow","ru":"Москва"}` {
+		t.Fatalf("Неверное значение %v (%v)", value, err)
+	}
+
+	var scanned LocalizedText
+	if err := scanned.Scan(value); err != nil || !reflect.DeepEqual(scanned, text) {
+		t.Errorf("Ожидалось %v, получено %v (%v)", text, scanned, err)
+	}
+
+	if text.Get("en") != "Moscow" || text.Get("zh") != "" {
+		t.Errorf("Неверные значения языков %v", text)
+	}
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestPointScan tests reading a point from the database driver values
+func TestPointScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		expected Point
+		valid    bool
+	}{
+		{"Scan_String", "(37.90629959106445,55.40879821777344)", Point{X: 37.90629959106445, Y: 55.40879821777344}, true},
+		{"Scan_Bytes", []byte("(129.77099609375,62.093299865722656)"), Point{X: 129.77099609375, Y: 62.093299865722656}, true},
+		{"Scan_Spaces", " ( -70.5 , 10 ) ", Point{X: -70.5, Y: 10}, true},
+		{"Scan_Null", nil, Point{}, true},
+		{"Scan_Type", 42, Point{}, false},
+		{"Scan_Format", "(1,2,3)", Point{}, false},
+		{"Scan_Number", "(east,2)", Point{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// NULL resets a previously scanned point
+			p := Point{X: 1, Y: 1}
+
+			err := p.Scan(tt.value)
+			if (err == nil) != tt.valid {
+				t.Fatalf("expected valid %v, got error %v", tt.valid, err)
+			}
+			if tt.valid && p != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, p)
+			}
+		})
+	}
+}
+
+// TestPointValue tests that saving and reading a point keeps full precision
+func TestPointValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		point    Point
+		expected string
+	}{
+		{"Value_Precision", Point{X: 37.90629959106445, Y: 55.40879821777344}, "(37.90629959106445,55.40879821777344)"},
+		{"Value_Small", Point{X: 0.0000001, Y: -0.5}, "(1e-07,-0.5)"},
+		{"Value_Zero", Point{}, "(0,0)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := tt.point.Value()
+			if err != nil || value != tt.expected {
+				t.Fatalf("expected %s, got %v (%v)", tt.expected, value, err)
+			}
+
+			var p Point
+			if err := p.Scan(value); err != nil || p != tt.point {
+				t.Errorf("round trip expected %+v, got %+v (%v)", tt.point, p, err)
+			}
+		})
+	}
+}
+
+// TestPointJSON tests the {lat, lon} JSON representation
+func TestPointJSON(t *testing.T) {
+	data, err := json.Marshal(Point{X: 37.4146, Y: 55.972599})
+	if err != nil || string(data) != `{"lat":55.972599,"lon":37.4146}` {
+		t.Fatalf("unexpected JSON %s (%v)", data, err)
+	}
+
+	tests := []struct {
+		name     string
+		data     string
+		expected Point
+		valid    bool
+	}{
+		{"JSON_Valid", `{"lat":55.972599,"lon":37.4146}`, Point{X: 37.4146, Y: 55.972599}, true},
+		{"JSON_Null", `null`, Point{}, true},
+		{"JSON_MissingLon", `{"lat":55.972599}`, Point{}, false},
+		{"JSON_Type", `{"lat":"north","lon":1}`, Point{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Point
+			err := json.Unmarshal([]byte(tt.data), &p)
+			if (err == nil) != tt.valid {
+				t.Fatalf("expected valid %v, got error %v", tt.valid, err)
+			}
+			if tt.valid && p != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, p)
+			}
+		})
+	}
+}
+
+// TestPointValidate tests the coordinate ranges
+func TestPointValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		point Point
+		valid bool
+	}{
+		{"Validate_Valid", Point{X: 37.4146, Y: 55.972599}, true},
+		{"Validate_Edges", Point{X: -180, Y: 90}, true},
+		{"Validate_Latitude", Point{X: 0, Y: 91}, false},
+		{"Validate_Longitude", Point{X: 180.5, Y: 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.point.Validate(); (err == nil) != tt.valid {
+				t.Errorf("expected valid %v, got error %v", tt.valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package geo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+)
+
+// Средний радиус Земли, км
+const EarthRadiusKm = 6371.0088
+
+// Ограничивающий прямоугольник в градусах.
+// Если MinLon больше MaxLon, прямоугольник пересекает меридиан 180°.
+type BBox struct {
+	MinLon float64
+	MinLat float64
+	MaxLon float64
+	MaxLat float64
+}
+
+// NewPoint возвращает точку по широте и долготе.
+// Точка хранит долготу в X и широту в Y, как тип point в PostgreSQL.
+func NewPoint(lat float64, lon float64) domain.Point {
+	return domain.Point{X: lon, Y: lat}
+}
+
+// Lat возвращает широту точки
+func Lat(p domain.Point) float64 {
+	return p.Y
+}
+
+// Lon возвращает долготу точки
+func Lon(p domain.Point) float64 {
+	return p.X
+}
+
+// Validate проверяет диапазоны широты [-90, 90] и долготы [-180, 180]
+func Validate(p domain.Point) error {
+	if math.IsNaN(p.Y) || p.Y < -90 || p.Y > 90 {
+		return i18n.NewError(i18n.MsgGeoLatitude, p.Y)
+	}
+	if math.IsNaN(p.X) || p.X < -180 || p.X > 180 {
+		return i18n.NewError(i18n.MsgGeoLongitude, p.X)
+	}
+	return nil
+}
+
+// Distance возвращает расстояние по большому кругу между точками в км (формула гаверсинусов)
+func Distance(a domain.Point, b domain.Point) float64 {
+	lat1, lat2 := radians(a.Y), radians(b.Y)
+	dlat := lat2 - lat1
+	dlon := radians(b.X - a.X)
+
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(min(h, 1)))
+}
+
+// Bearing возвращает начальный азимут из точки a в точку b в градусах [0, 360), 0 - север
+func Bearing(a domain.Point, b domain.Point) float64 {
+	lat1, lat2 := radians(a.Y), radians(b.Y)
+	dlon := radians(b.X - a.X)
+
+	y := math.Sin(dlon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dlon)
+
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// BoundingBox возвращает прямоугольник, содержащий круг радиуса radiusKm вокруг точки.
+// Круг, содержащий полюс, дает прямоугольник на все долготы.
+func BoundingBox(center domain.Point, radiusKm float64) BBox {
+	dlat := degrees(radiusKm / EarthRadiusKm)
+
+	box := BBox{MinLat: center.Y - dlat, MaxLat: center.Y + dlat, MinLon: -180, MaxLon: 180}
+	if box.MinLat <= -90 || box.MaxLat >= 90 {
+		box.MinLat = max(box.MinLat, -90)
+		box.MaxLat = min(box.MaxLat, 90)
+		return box
+	}
+
+	// Наибольшее отклонение долготы достигается на касательной к кругу
+	dlon := degrees(math.Asin(math.Sin(radiusKm/EarthRadiusKm) / math.Cos(radians(center.Y))))
+	if dlon >= 180 {
+		return box
+	}
+
+	box.MinLon = normalizeLon(center.X - dlon)
+	box.MaxLon = normalizeLon(center.X + dlon)
+	return box
+}
+
+// ParseBBox разбирает прямоугольник в порядке GeoJSON: minLon,minLat,maxLon,maxLat
+func ParseBBox(value string) (BBox, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return BBox{}, i18n.NewError(i18n.MsgGeoBBoxFormat)
+	}
+
+	var numbers [4]float64
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BBox{}, i18n.NewError(i18n.MsgGeoBBoxCoordinate, part)
+		}
+		numbers[i] = number
+	}
+
+	box := BBox{MinLon: numbers[0], MinLat: numbers[1], MaxLon: numbers[2], MaxLat: numbers[3]}
+	for _, corner := range []domain.Point{{X: box.MinLon, Y: box.MinLat}, {X: box.MaxLon, Y: box.MaxLat}} {
+		if err := Validate(corner); err != nil {
+			return BBox{}, err
+		}
+	}
+	if box.MinLat > box.MaxLat {
+		return BBox{}, i18n.NewError(i18n.MsgGeoBBoxLatitude)
+	}
+
+	return box, nil
+}
+
+// Contains проверяет попадание точки в прямоугольник
+func (box BBox) Contains(p domain.Point) bool {
+	if p.Y < box.MinLat || p.Y > box.MaxLat {
+		return false
+	}
+	if box.Wraps() {
+		return p.X >= box.MinLon || p.X <= box.MaxLon
+	}
+	return p.X >= box.MinLon && p.X <= box.MaxLon
+}
+
+// Wraps проверяет, пересекает ли прямоугольник меридиан 180°
+func (box BBox) Wraps() bool {
+	return box.MinLon > box.MaxLon
+}
+
+// Center возвращает центр прямоугольника
+func (box BBox) Center() domain.Point {
+	maxLon := box.MaxLon
+	if box.Wraps() {
+		maxLon += 360
+	}
+	return domain.Point{X: normalizeLon((box.MinLon + maxLon) / 2), Y: (box.MinLat + box.MaxLat) / 2}
+}
+
+// normalizeLon приводит долготу к диапазону [-180, 180]
+func normalizeLon(lon float64) float64 {
+	for lon > 180 {
+		lon -= 360
+	}
+	for lon < -180 {
+		lon += 360
+	}
+	return lon
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
@@ -0,0 +1,133 @@
+package geo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+)
+
+var (
+	svo = NewPoint(55.972599, 37.414600)  // Шереметьево
+	led = NewPoint(59.800301, 30.262501)  // Пулково
+	pkc = NewPoint(53.167900, 158.453995) // Елизово
+	dyr = NewPoint(64.734901, 177.740997) // Анадырь
+)
+
+// TestDistance тестирует расстояние по большому кругу
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     domain.Point
+		expected float64
+	}{
+		{"Distance_SVO_LED", svo, led, 599.3},
+		{"Distance_Same", svo, svo, 0},
+		{"Distance_Meridian", NewPoint(0, 0), NewPoint(1, 0), 111.2},
+		{"Distance_Antimeridian", NewPoint(0, 179.5), NewPoint(0, -179.5), 111.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if distance := Distance(tt.a, tt.b); math.Abs(distance-tt.expected) > 0.1 {
+				t.Errorf("Ожидалось %v км, получено %v", tt.expected, distance)
+			}
+			if Distance(tt.a, tt.b) != Distance(tt.b, tt.a) {
+				t.Errorf("Расстояние зависит от направления")
+			}
+		})
+	}
+}
+
+// TestBearing тестирует начальный азимут
+func TestBearing(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     domain.Point
+		expected float64
+	}{
+		{"Bearing_North", NewPoint(0, 0), NewPoint(10, 0), 0},
+		{"Bearing_East", NewPoint(0, 0), NewPoint(0, 10), 90},
+		{"Bearing_South", NewPoint(10, 0), NewPoint(0, 0), 180},
+		{"Bearing_West", NewPoint(0, 0), NewPoint(0, -10), 270},
+		{"Bearing_SVO_LED", svo, led, 318.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if bearing := Bearing(tt.a, tt.b); math.Abs(bearing-tt.expected) > 0.1 {
+				t.Errorf("Ожидался азимут %v, получено %v", tt.expected, bearing)
+			}
+		})
+	}
+}
+
+// TestBoundingBox тестирует прямоугольник вокруг круга
+func TestBoundingBox(t *testing.T) {
+	box := BoundingBox(svo, 700)
+	if !box.Contains(led) || box.Contains(pkc) || box.Wraps() {
+		t.Errorf("Неверный прямоугольник %+v", box)
+	}
+
+	// Круг через меридиан 180° дает прямоугольник с переходом
+	box = BoundingBox(dyr, 500)
+	if !box.Wraps() || !box.Contains(NewPoint(64.7, -178)) || box.Contains(NewPoint(64.7, 0)) {
+		t.Errorf("Ожидался прямоугольник через меридиан 180°, получено %+v", box)
+	}
+
+	// Круг с полюсом охватывает все долготы
+	box = BoundingBox(NewPoint(89, 0), 500)
+	if box.MinLon != -180 || box.MaxLon != 180 || box.MaxLat != 90 {
+		t.Errorf("Ожидался прямоугольник до полюса, получено %+v", box)
+	}
+}
+
+// TestParseBBox тестирует разбор прямоугольника
+func TestParseBBox(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		valid bool
+	}{
+		{"BBox_Valid", "30,50,40,60", true},
+		{"BBox_Antimeridian", "170,50,-170,70", true},
+		{"BBox_Count", "30,50,40", false},
+		{"BBox_Number", "30,50,east,60", false},
+		{"BBox_Latitude", "30,50,40,95", false},
+		{"BBox_Order", "30,60,40,50", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBBox(tt.value)
+			if (err == nil) != tt.valid {
+				t.Errorf("Ожидалась корректность %v, получена ошибка %v", tt.valid, err)
+			}
+		})
+	}
+
+	box, _ := ParseBBox("170,50,-170,70")
+	if center := box.Center(); math.Abs(Lon(center)-180) > 1e-9 || Lat(center) != 60 {
+		t.Errorf("Неверный центр прямоугольника %+v", center)
+	}
+}
+
+// TestNewLineFeature тестирует разрезание линии на меридиане 180°
+func TestNewLineFeature(t *testing.T) {
+	line := NewLineFeature("", NewPoint(60, 30), NewPoint(56, 37), nil)
+	if line.Geometry.Type != "LineString" {
+		t.Errorf("Ожидалась LineString, получено %v", line.Geometry.Type)
+	}
+
+	line = NewLineFeature("", NewPoint(64, 170), NewPoint(66, -170), nil)
+	parts, ok := line.Geometry.Coordinates.([][][]float64)
+	if line.Geometry.Type != "MultiLineString" || !ok || len(parts) != 2 {
+		t.Fatalf("Ожидалась MultiLineString из двух частей, получено %+v", line.Geometry)
+	}
+
+	expected := [][][]float64{{{170, 64}, {180, 65}}, {{-180, 65}, {-170, 66}}}
+	if !reflect.DeepEqual(parts, expected) {
+		t.Errorf("Ожидались части %v, получено %v", expected, parts)
+	}
+}
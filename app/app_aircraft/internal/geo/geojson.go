@@ -0,0 +1,74 @@
+package geo
+
+import (
+	"math"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+)
+
+// Тип содержимого GeoJSON (RFC 7946)
+const GeoJSONContentType = "application/geo+json"
+
+// Коллекция объектов GeoJSON
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Объект GeoJSON: геометрия и свойства
+type Feature struct {
+	Type       string         `json:"type"`
+	Id         string         `json:"id,omitempty"`
+	Geometry   Geometry       `json:"geometry"`
+	Properties map[string]any `json:"properties"`
+}
+
+// Геометрия GeoJSON, координаты в порядке [долгота, широта]
+type Geometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// NewFeatureCollection возвращает коллекцию объектов, пустая коллекция содержит пустой список
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: "FeatureCollection", Features: features}
+}
+
+// NewPointFeature возвращает объект с геометрией Point
+func NewPointFeature(id string, p domain.Point, properties map[string]any) Feature {
+	return Feature{Type: "Feature", Id: id, Properties: properties,
+		Geometry: Geometry{Type: "Point", Coordinates: position(p)}}
+}
+
+// NewLineFeature возвращает объект с геометрией LineString из точки from в точку to.
+// Линия, пересекающая меридиан 180°, разрезается на две части MultiLineString, как требует RFC 7946.
+func NewLineFeature(id string, from domain.Point, to domain.Point, properties map[string]any) Feature {
+	feature := Feature{Type: "Feature", Id: id, Properties: properties,
+		Geometry: Geometry{Type: "LineString", Coordinates: [][]float64{position(from), position(to)}}}
+
+	if math.Abs(to.X-from.X) <= 180 {
+		return feature
+	}
+
+	// Переход через 180°: долгота конца смещается на 360°, чтобы найти широту пересечения
+	edge := 180.0
+	if from.X < 0 {
+		edge = -180
+	}
+	toX := to.X + 2*edge
+	lat := from.Y + (to.Y-from.Y)*(edge-from.X)/(toX-from.X)
+
+	feature.Geometry = Geometry{Type: "MultiLineString", Coordinates: [][][]float64{
+		{position(from), {edge, lat}},
+		{{-edge, lat}, position(to)},
+	}}
+	return feature
+}
+
+// position возвращает координаты точки в порядке GeoJSON
+func position(p domain.Point) []float64 {
+	return []float64{p.X, p.Y}
+}
@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Язык сообщений по умолчанию
+const DefaultLanguage = "ru"
+
+// Error - ошибка с ключом сообщения каталога и параметрами сообщения.
+// Текст ошибки выводится на языке по умолчанию, на языке запроса ее выводит Text.
+type Error struct {
+	Key  string
+	Args []any
+}
+
+func (e *Error) Error() string {
+	return Text(nil, e.Key, e.Args...)
+}
+
+// NewError возвращает ошибку с ключом сообщения каталога
+func NewError(key string, args ...any) error {
+	return &Error{Key: key, Args: args}
+}
+
+// KeyOf возвращает ключ и параметры сообщения ошибки каталога
+func KeyOf(err error) (string, []any, bool) {
+	var ierr *Error
+	if errors.As(err, &ierr) {
+		return ierr.Key, ierr.Args, true
+	}
+	return "", nil, false
+}
+
+// Text возвращает сообщение key на первом языке цепочки chain, для которого есть перевод,
+// иначе на языке по умолчанию. Для неизвестного ключа возвращается сам ключ.
+func Text(chain []string, key string, args ...any) string {
+	for _, lang := range chain {
+		if template, ok := messages[lang][key]; ok {
+			return format(template, args)
+		}
+	}
+	if template, ok := messages[DefaultLanguage][key]; ok {
+		return format(template, args)
+	}
+	return key
+}
+
+func format(template string, args []any) string {
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
+
+// Validation возвращает сообщение валидации свойства с ключом сообщения каталога
+func Validation(property string, key string, args ...any) model.Validation {
+	return model.Validation{Property: property, Message: Text(nil, key, args...), Code: key, Args: args}
+}
+
+// ValidationOf возвращает сообщение валидации свойства по ошибке.
+// Для ошибки каталога сохраняется ключ, чтобы сообщение можно было перевести.
+func ValidationOf(property string, err error) model.Validation {
+	if key, args, ok := KeyOf(err); ok {
+		return Validation(property, key, args...)
+	}
+	return model.Validation{Property: property, Message: err.Error()}
+}
+
+// LocalizeValidations переводит сообщения валидации с ключом каталога на язык цепочки chain
+func LocalizeValidations(validations []model.Validation, chain []string) []model.Validation {
+	localized := make([]model.Validation, len(validations))
+	for i, validation := range validations {
+		if validation.Code != "" {
+			validation.Message = Text(chain, validation.Code, validation.Args...)
+		}
+		localized[i] = validation
+	}
+	return localized
+}
+
+// Languages возвращает языки каталога
+func Languages() []string {
+	var languages []string
+	for lang := range messages {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// Keys возвращает ключи сообщений всех языков каталога
+func Keys() []string {
+	unique := make(map[string]bool)
+	for _, texts := range messages {
+		for key := range texts {
+			unique[key] = true
+		}
+	}
+
+	var keys []string
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,105 @@
+package i18n
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Параметры форматирования сообщения
+var formatVerb = regexp.MustCompile(`%[-+# 0]*[0-9]*[a-zA-Z]`)
+
+// TestCatalog_Translated проверяет, что каждый ключ переведен на все языки каталога
+// с одинаковыми параметрами форматирования
+func TestCatalog_Translated(t *testing.T) {
+	for _, key := range Keys() {
+		expected := formatVerb.FindAllString(messages[DefaultLanguage][key], -1)
+
+		for _, lang := range Languages() {
+			text, ok := messages[lang][key]
+			if !ok || text == "" {
+				t.Errorf("Нет перевода ключа '%s' на язык '%s'", key, lang)
+				continue
+			}
+			if verbs := formatVerb.FindAllString(text, -1); !reflect.DeepEqual(verbs, expected) {
+				t.Errorf("Параметры ключа '%s' на языке '%s' %v отличаются от %v", key, lang, verbs, expected)
+			}
+		}
+	}
+}
+
+// TestCatalog_Keys проверяет, что все объявленные ключи сообщений есть в каталоге
+func TestCatalog_Keys(t *testing.T) {
+	file, err := parser.ParseFile(token.NewFileSet(), "messages.go", nil, 0)
+	if err != nil {
+		t.Fatalf("Ошибка разбора messages.go: %v", err)
+	}
+
+	declared := 0
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok || len(spec.Values) != 1 {
+			return true
+		}
+		literal, ok := spec.Values[0].(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			return true
+		}
+
+		key, _ := strconv.Unquote(literal.Value)
+		declared++
+		for _, lang := range Languages() {
+			if _, ok := messages[lang][key]; !ok {
+				t.Errorf("Ключ %s ('%s') не переведен на язык '%s'", spec.Names[0].Name, key, lang)
+			}
+		}
+		return true
+	})
+
+	if declared != len(Keys()) {
+		t.Errorf("Объявлено %d ключей, в каталоге %d", declared, len(Keys()))
+	}
+}
+
+// TestText тестирует выбор языка сообщения и подстановку параметров
+func TestText(t *testing.T) {
+	tests := []struct {
+		name     string
+		chain    []string
+		key      string
+		args     []any
+		expected string
+	}{
+		{"Text_Default", nil, MsgAircraftNotFound, []any{"773"}, "Самолет с кодом '773' не существует!"},
+		{"Text_English", []string{"en"}, MsgAircraftNotFound, []any{"773"}, "Aircraft with code '773' does not exist!"},
+		{"Text_Chain", []string{"de", "en", "ru"}, MsgSearchSize, []any{1, 50}, "Result size must be from 1 to 50"},
+		{"Text_Unknown", []string{"en"}, "unknown.key", nil, "unknown.key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if text := Text(tt.chain, tt.key, tt.args...); text != tt.expected {
+				t.Errorf("Ожидалось '%s', получено '%s'", tt.expected, text)
+			}
+		})
+	}
+}
+
+// TestValidationOf тестирует перевод сообщения валидации по ключу ошибки каталога
+func TestValidationOf(t *testing.T) {
+	validation := ValidationOf("cursor", NewError(MsgCursorInvalid))
+	if validation.Code != MsgCursorInvalid || validation.Message != "Неверный курсор страницы" {
+		t.Fatalf("Неверная валидация %+v", validation)
+	}
+
+	localized := LocalizeValidations([]model.Validation{validation}, []string{"en"})
+	if localized[0].Message != "Invalid page cursor" || localized[0].Property != "cursor" {
+		t.Errorf("Неверный перевод валидации %+v", localized[0])
+	}
+}
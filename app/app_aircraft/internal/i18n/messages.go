@@ -0,0 +1,290 @@
+package i18n
+
+// Ключи сообщений каталога. Ключ передается клиенту в поле Code результата и сообщений валидации.
+const (
+	MsgRequestArguments = "request.arguments"
+	MsgRequestBody      = "request.body"
+	MsgRequestCode      = "request.code_missing"
+	MsgRequestSeat      = "request.seat_missing"
+	MsgRequestId        = "request.id_invalid"
+	MsgRequestFormat    = "request.format_unknown"
+	MsgRequestLang      = "request.lang_invalid"
+	MsgRequestCascade   = "request.cascade_unknown"
+	MsgRequestIfMatch   = "request.if_match_required"
+	MsgRequestPatch     = "request.patch_invalid"
+	MsgPatchObject      = "patch.object_expected"
+	MsgPatchCode        = "patch.code_mismatch"
+	MsgVersionMismatch  = "version.mismatch"
+
+	MsgErrorDetail      = "error.detail"
+	MsgErrorInternal    = "error.internal"
+	MsgErrorTimeout     = "error.timeout"
+	MsgErrorUnavailable = "error.unavailable"
+	MsgErrorQuery       = "error.query"
+	MsgErrorUnique      = "error.unique_violation"
+	MsgErrorReference   = "error.reference_violation"
+
+	MsgListInvalid      = "list.invalid"
+	MsgListSort         = "list.sort_unsupported"
+	MsgListFilter       = "list.filter_unsupported"
+	MsgFilterExpression = "filter.expression_invalid"
+	MsgFilterValue      = "filter.value_missing"
+	MsgFilterOperation  = "filter.operation_unknown"
+	MsgFilterLikeNumber = "filter.like_number"
+	MsgFilterNumber     = "filter.number_expected"
+	MsgFilterInteger    = "filter.integer_expected"
+	MsgFilterLikeDate   = "filter.like_date"
+	MsgFilterDate       = "filter.date_expected"
+
+	MsgPageInvalid   = "page.invalid"
+	MsgPageTotal     = "page.total_unknown"
+	MsgCursorInvalid = "cursor.invalid"
+	MsgCursorSort    = "cursor.sort_mismatch"
+
+	MsgAircraftNotFound = "aircraft.not_found"
+	MsgAircraftExists   = "aircraft.exists"
+	MsgAircraftInUse    = "aircraft.in_use"
+	MsgDependencySeats  = "aircraft.dependency_seats"
+	MsgDependencyFuture = "aircraft.dependency_future_flights"
+	MsgDependencyPast   = "aircraft.dependency_past_flights"
+	MsgSeatNotFound     = "seat.not_found"
+	MsgSeatExists       = "seat.exists"
+
+	MsgLayoutInvalid      = "layout.invalid"
+	MsgLayoutEmpty        = "layout.empty"
+	MsgLayoutSection      = "layout.section_invalid"
+	MsgLayoutClass        = "layout.class_unknown"
+	MsgLayoutRow          = "layout.row_invalid"
+	MsgLayoutRows         = "layout.rows_invalid"
+	MsgLayoutSeats        = "layout.seats_invalid"
+	MsgLayoutSeatRepeated = "layout.seat_repeated"
+	MsgLayoutLetter       = "layout.letter_repeated"
+
+	MsgAirportNotFound = "airport.not_found"
+	MsgAirportExists   = "airport.exists"
+	MsgAirportInUse    = "airport.in_use"
+	MsgFlightNotFound  = "flight.not_found"
+
+	MsgSearchInvalid = "search.invalid"
+	MsgSearchQuery   = "search.query_missing"
+	MsgSearchType    = "search.type_unknown"
+	MsgSearchSize    = "search.size_range"
+	MsgSearchCursor  = "search.cursor_unsupported"
+
+	MsgNearbyInvalid     = "nearby.invalid"
+	MsgGeoPointPair      = "geo.point_incomplete"
+	MsgGeoPointMissing   = "geo.point_missing"
+	MsgGeoRadiusBBox     = "geo.radius_or_bbox"
+	MsgGeoRadius         = "geo.radius_positive"
+	MsgGeoLatitude       = "geo.latitude_range"
+	MsgGeoLongitude      = "geo.longitude_range"
+	MsgGeoBBoxFormat     = "geo.bbox_format"
+	MsgGeoBBoxCoordinate = "geo.bbox_coordinate"
+	MsgGeoBBoxLatitude   = "geo.bbox_latitude_order"
+
+	MsgInputInvalid      = "input.invalid"
+	MsgFieldRequired     = "field.required"
+	MsgFieldInvalid      = "field.invalid"
+	MsgFieldAircraftCode = "field.aircraft_code"
+	MsgFieldAirportCode  = "field.airport_code"
+	MsgFieldSeatNo       = "field.seat_no"
+	MsgFieldOneOf        = "field.one_of"
+	MsgFieldGreater      = "field.greater"
+	MsgFieldMin          = "field.min"
+	MsgFieldMax          = "field.max"
+	MsgFieldLength       = "field.max_length"
+	MsgFieldTimezone     = "field.timezone"
+)
+
+// Сообщения по языкам. Параметры сообщения подставляются по порядку, поэтому
+// переводы одного ключа должны содержать одинаковые параметры форматирования.
+var messages = map[string]map[string]string{
+	"ru": {
+		MsgRequestArguments: "Ошибка чтения аргументов запроса",
+		MsgRequestBody:      "Ошибка получения данных",
+		MsgRequestCode:      "Ошибка получения шифра. Аргумент 'code' не задан",
+		MsgRequestSeat:      "Ошибка получения шифра. Аргументы 'code' и 'seat' не заданы",
+		MsgRequestId:        "Ошибка получения идентификатора. Аргумент 'id' задан неверно",
+		MsgRequestFormat:    "Неизвестный формат '%s', допустимы json, geojson",
+		MsgRequestLang:      "Неверный тег языка '%s'",
+		MsgRequestCascade:   "Неизвестный режим каскадного удаления '%s', допустим seats",
+		MsgRequestIfMatch:   "Для изменения '%v' требуется заголовок If-Match с версией объекта",
+		MsgRequestPatch:     "Неверный документ изменений JSON Merge Patch",
+		MsgPatchObject:      "Документ изменений должен быть объектом JSON",
+		MsgPatchCode:        "Код '%v' в документе изменений не совпадает с кодом '%v' в адресе",
+		MsgVersionMismatch:  "Объект '%v' изменен после чтения, текущая версия %v",
+
+		MsgErrorDetail:      "Ошибка: %v",
+		MsgErrorInternal:    "Внутренняя ошибка сервиса",
+		MsgErrorTimeout:     "Превышено время выполнения запроса",
+		MsgErrorUnavailable: "База данных недоступна",
+		MsgErrorQuery:       "Ошибка запроса данных '%s'",
+		MsgErrorUnique:      "Запись нарушает ограничение уникальности '%s'",
+		MsgErrorReference:   "Запись нарушает ограничение ссылочной целостности '%s'",
+
+		MsgListInvalid:      "Неверные параметры сортировки или фильтра",
+		MsgListSort:         "Сортировка по полю '%s' не поддерживается",
+		MsgListFilter:       "Фильтр по полю '%s' не поддерживается",
+		MsgFilterExpression: "Неверное выражение фильтра '%s'",
+		MsgFilterValue:      "Не задано значение фильтра '%s'",
+		MsgFilterOperation:  "Неизвестная операция фильтра '%s'",
+		MsgFilterLikeNumber: "Операция '~' не применима к числовому полю '%s'",
+		MsgFilterNumber:     "Поле '%s' ожидает число, получено '%s'",
+		MsgFilterInteger:    "Поле '%s' ожидает целое число, получено '%s'",
+		MsgFilterLikeDate:   "Операция '~' не применима к полю даты '%s'",
+		MsgFilterDate:       "Поле '%s' ожидает дату RFC3339, получено '%s'",
+
+		MsgPageInvalid:   "Неверные параметры страницы",
+		MsgPageTotal:     "Неизвестный способ подсчета total '%s', допустимы exact, estimated, none",
+		MsgCursorInvalid: "Неверный курсор страницы",
+		MsgCursorSort:    "Курсор выдан для другой сортировки, начните с первой страницы",
+
+		MsgAircraftNotFound: "Самолет с кодом '%v' не существует!",
+		MsgAircraftExists:   "Самолет с кодом '%v' уже существует!",
+		MsgAircraftInUse:    "Самолет с кодом '%v' используется и не может быть удален!",
+		MsgDependencySeats:  "Мест в самолете: %v, удалите их вместе с самолетом параметром cascade=seats",
+		MsgDependencyFuture: "Будущих рейсов самолета: %v",
+		MsgDependencyPast:   "Выполненных рейсов самолета: %v",
+		MsgSeatNotFound:     "Место '%v' в самолете с кодом '%v' не существует!",
+		MsgSeatExists:       "Место '%v' в самолете с кодом '%v' уже существует!",
+
+		MsgLayoutInvalid:      "Ошибка разбора компоновки салона",
+		MsgLayoutEmpty:        "Пустое описание компоновки салона",
+		MsgLayoutSection:      "Неверный формат секции '%s', ожидается '<класс> rows <ряды> <места>'",
+		MsgLayoutClass:        "Неизвестный класс обслуживания '%s', допустимы: %s",
+		MsgLayoutRow:          "Неверный номер ряда '%s'",
+		MsgLayoutRows:         "Неверный диапазон рядов '%s'",
+		MsgLayoutSeats:        "Неверное обозначение мест '%s'",
+		MsgLayoutSeatRepeated: "Место '%s' задано повторно (классы %s и %s)",
+		MsgLayoutLetter:       "Место '%c' задано повторно в '%s'",
+
+		MsgAirportNotFound: "Аэропорт с кодом '%v' не существует!",
+		MsgAirportExists:   "Аэропорт с кодом '%v' уже существует!",
+		MsgAirportInUse:    "Аэропорт с кодом '%v' используется в рейсах (%v) и не может быть удален!",
+		MsgFlightNotFound:  "Рейс с идентификатором '%v' не существует!",
+
+		MsgSearchInvalid: "Неверные параметры поиска",
+		MsgSearchQuery:   "Не задана строка поиска",
+		MsgSearchType:    "Тип поиска должен быть aircraft или airport",
+		MsgSearchSize:    "Размер выдачи должен быть от %d до %d",
+		MsgSearchCursor:  "Курсор поддерживается для поиска только с параметром sort, используйте offset",
+
+		MsgNearbyInvalid:     "Неверные параметры поиска аэропортов рядом",
+		MsgGeoPointPair:      "Точка задается параметрами lat и lon",
+		MsgGeoPointMissing:   "Не задана точка lat, lon",
+		MsgGeoRadiusBBox:     "Задайте radius или bbox, но не оба",
+		MsgGeoRadius:         "Радиус должен быть больше 0",
+		MsgGeoLatitude:       "Широта должна быть в диапазоне от -90 до 90, получено %v",
+		MsgGeoLongitude:      "Долгота должна быть в диапазоне от -180 до 180, получено %v",
+		MsgGeoBBoxFormat:     "Прямоугольник задается как minLon,minLat,maxLon,maxLat",
+		MsgGeoBBoxCoordinate: "Неверная координата прямоугольника '%s'",
+		MsgGeoBBoxLatitude:   "Минимальная широта прямоугольника больше максимальной",
+
+		MsgInputInvalid:      "Неверные входные данные",
+		MsgFieldRequired:     "Значение обязательно",
+		MsgFieldInvalid:      "Неверное значение '%v'",
+		MsgFieldAircraftCode: "Код самолета должен состоять из 3 заглавных латинских букв или цифр, получено '%v'",
+		MsgFieldAirportCode:  "Код аэропорта должен состоять из 3 заглавных латинских букв, получено '%v'",
+		MsgFieldSeatNo:       "Место задается номером ряда и буквой, например 12A, получено '%v'",
+		MsgFieldOneOf:        "Допустимые значения: %s, получено '%v'",
+		MsgFieldGreater:      "Значение должно быть больше %s, получено %v",
+		MsgFieldMin:          "Значение должно быть не меньше %s, получено %v",
+		MsgFieldMax:          "Значение должно быть не больше %s, получено %v",
+		MsgFieldLength:       "Длина не должна превышать %s символов",
+		MsgFieldTimezone:     "Неизвестный часовой пояс IANA '%v'",
+	},
+	"en": {
+		MsgRequestArguments: "Failed to read request arguments",
+		MsgRequestBody:      "Failed to read request data",
+		MsgRequestCode:      "Failed to get the code. Argument 'code' is not set",
+		MsgRequestSeat:      "Failed to get the code. Arguments 'code' and 'seat' are not set",
+		MsgRequestId:        "Failed to get the identifier. Argument 'id' is invalid",
+		MsgRequestFormat:    "Unknown format '%s', allowed formats are json, geojson",
+		MsgRequestLang:      "Invalid language tag '%s'",
+		MsgRequestCascade:   "Unknown cascade delete mode '%s', allowed mode is seats",
+		MsgRequestIfMatch:   "Changing '%v' requires the If-Match header with the object version",
+		MsgRequestPatch:     "Invalid JSON Merge Patch document",
+		MsgPatchObject:      "The patch document must be a JSON object",
+		MsgPatchCode:        "Code '%v' in the patch document does not match code '%v' in the URL",
+		MsgVersionMismatch:  "Object '%v' was changed after it was read, the current version is %v",
+
+		MsgErrorDetail:      "Error: %v",
+		MsgErrorInternal:    "Internal service error",
+		MsgErrorTimeout:     "Request execution time exceeded",
+		MsgErrorUnavailable: "Database is unavailable",
+		MsgErrorQuery:       "Data query '%s' failed",
+		MsgErrorUnique:      "The record violates unique constraint '%s'",
+		MsgErrorReference:   "The record violates foreign key constraint '%s'",
+
+		MsgListInvalid:      "Invalid sort or filter parameters",
+		MsgListSort:         "Sorting by field '%s' is not supported",
+		MsgListFilter:       "Filtering by field '%s' is not supported",
+		MsgFilterExpression: "Invalid filter expression '%s'",
+		MsgFilterValue:      "Filter value is missing in '%s'",
+		MsgFilterOperation:  "Unknown filter operation in '%s'",
+		MsgFilterLikeNumber: "Operation '~' is not applicable to numeric field '%s'",
+		MsgFilterNumber:     "Field '%s' expects a number, got '%s'",
+		MsgFilterInteger:    "Field '%s' expects an integer, got '%s'",
+		MsgFilterLikeDate:   "Operation '~' is not applicable to date field '%s'",
+		MsgFilterDate:       "Field '%s' expects an RFC3339 date, got '%s'",
+
+		MsgPageInvalid:   "Invalid page parameters",
+		MsgPageTotal:     "Unknown total mode '%s', allowed modes are exact, estimated, none",
+		MsgCursorInvalid: "Invalid page cursor",
+		MsgCursorSort:    "The cursor was issued for a different sort order, start from the first page",
+
+		MsgAircraftNotFound: "Aircraft with code '%v' does not exist!",
+		MsgAircraftExists:   "Aircraft with code '%v' already exists!",
+		MsgAircraftInUse:    "Aircraft with code '%v' is in use and cannot be deleted!",
+		MsgDependencySeats:  "Aircraft seats: %v, delete them with the aircraft using cascade=seats",
+		MsgDependencyFuture: "Future flights of the aircraft: %v",
+		MsgDependencyPast:   "Past flights of the aircraft: %v",
+		MsgSeatNotFound:     "Seat '%v' of aircraft with code '%v' does not exist!",
+		MsgSeatExists:       "Seat '%v' of aircraft with code '%v' already exists!",
+
+		MsgLayoutInvalid:      "Failed to parse the cabin layout",
+		MsgLayoutEmpty:        "The cabin layout is empty",
+		MsgLayoutSection:      "Invalid section format '%s', expected '<class> rows <rows> <seats>'",
+		MsgLayoutClass:        "Unknown fare class '%s', allowed classes are: %s",
+		MsgLayoutRow:          "Invalid row number '%s'",
+		MsgLayoutRows:         "Invalid row range '%s'",
+		MsgLayoutSeats:        "Invalid seat letters '%s'",
+		MsgLayoutSeatRepeated: "Seat '%s' is defined twice (classes %s and %s)",
+		MsgLayoutLetter:       "Seat '%c' is defined twice in '%s'",
+
+		MsgAirportNotFound: "Airport with code '%v' does not exist!",
+		MsgAirportExists:   "Airport with code '%v' already exists!",
+		MsgAirportInUse:    "Airport with code '%v' is used by flights (%v) and cannot be deleted!",
+		MsgFlightNotFound:  "Flight with identifier '%v' does not exist!",
+
+		MsgSearchInvalid: "Invalid search parameters",
+		MsgSearchQuery:   "The search string is not set",
+		MsgSearchType:    "Search type must be aircraft or airport",
+		MsgSearchSize:    "Result size must be from %d to %d",
+		MsgSearchCursor:  "Search supports the cursor only with the sort parameter, use offset",
+
+		MsgNearbyInvalid:     "Invalid nearby airport search parameters",
+		MsgGeoPointPair:      "A point is set by both lat and lon parameters",
+		MsgGeoPointMissing:   "The point lat, lon is not set",
+		MsgGeoRadiusBBox:     "Set either radius or bbox, not both",
+		MsgGeoRadius:         "Radius must be greater than 0",
+		MsgGeoLatitude:       "Latitude must be in the range from -90 to 90, got %v",
+		MsgGeoLongitude:      "Longitude must be in the range from -180 to 180, got %v",
+		MsgGeoBBoxFormat:     "A bounding box is set as minLon,minLat,maxLon,maxLat",
+		MsgGeoBBoxCoordinate: "Invalid bounding box coordinate '%s'",
+		MsgGeoBBoxLatitude:   "The minimum latitude of the bounding box is greater than the maximum",
+
+		MsgInputInvalid:      "Invalid input data",
+		MsgFieldRequired:     "A value is required",
+		MsgFieldInvalid:      "Invalid value '%v'",
+		MsgFieldAircraftCode: "Aircraft code must consist of 3 uppercase Latin letters or digits, got '%v'",
+		MsgFieldAirportCode:  "Airport code must consist of 3 uppercase Latin letters, got '%v'",
+		MsgFieldSeatNo:       "A seat is set by a row number and a letter, for example 12A, got '%v'",
+		MsgFieldOneOf:        "Allowed values are: %s, got '%v'",
+		MsgFieldGreater:      "Value must be greater than %s, got %v",
+		MsgFieldMin:          "Value must be at least %s, got %v",
+		MsgFieldMax:          "Value must be at most %s, got %v",
+		MsgFieldLength:       "Length must not exceed %s characters",
+		MsgFieldTimezone:     "Unknown IANA time zone '%v'",
+	},
+}
@@ -15,9 +15,55 @@ type AircraftData struct {
 	Code     string  
 	NameRu   string 
 	NameEn   string  
+	Names    map[string]string
 	Range  	 int 	
 	SeatCount int
 	Seats *[]SeatData
+	Version  string
+}
+
+// Самолет с названием на одном языке, выбранном по Accept-Language или lang
+type LocalizedAircraftData struct {
+	Code      string
+	Name      string
+	Lang      string
+	Range     int
+	SeatCount int
+	Seats     *[]SeatData
+	Version   string
+}
+
+// Данные места в салоне
+type SeatItemData struct {
+	SeatNo   string
+	SeatType string
+}
+
+// Схема мест самолета
+type AircraftSeatsData struct {
+	Code      string
+	SeatCount int
+	Seats *[]SeatData
+	Items *[]SeatItemData
+}
+
+// Записи, ссылающиеся на самолет: места, будущие и выполненные рейсы
+type AircraftDependencies struct {
+	Code          string
+	Seats         int
+	FutureFlights int
+	PastFlights   int
+}
+
+// Результат замены компоновки салона
+type AircraftLayoutData struct {
+	Code      string
+	DryRun    bool
+	SeatCount int
+	Seats *[]SeatData
+	Added   *[]SeatItemData
+	Removed *[]SeatItemData
+	Changed *[]SeatItemData
 }
 
 // Общие данные аэропорта
@@ -27,12 +73,65 @@ type AirportData struct {
 	NameEn     string
 	CityRu     string
 	CityEn     string	
+	Names      map[string]string
+	CityNames  map[string]string
+	Latitude   float64
+	Longitude  float64
 	Timezone   string
+	Version    string
 
 	LastDepartures *[]AirportFlightData
 	LastArrivals *[]AirportFlightData
 }
 
+// Найденный фрагмент поля: позиции начала и конца в символах исходного значения
+type SearchMatch struct {
+	Field string
+	Start int
+	End   int
+}
+
+// Результат поиска: самолет или аэропорт с оценкой и найденными фрагментами
+type SearchItemData struct {
+	Type    string
+	Code    string
+	NameRu  string
+	NameEn  string
+	CityRu  string
+	CityEn  string
+	Score   float64
+	Matches []SearchMatch
+}
+
+// Аэропорт с названием и городом на одном языке, выбранном по Accept-Language или lang
+type LocalizedAirportData struct {
+	Code      string
+	Name      string
+	City      string
+	Lang      string
+	Latitude  float64
+	Longitude float64
+	Timezone  string
+	Version   string
+
+	LastDepartures *[]AirportFlightData
+	LastArrivals   *[]AirportFlightData
+}
+
+// Аэропорт рядом с точкой: расстояние по большому кругу в км и начальный азимут из точки в градусах
+type AirportGeoData struct {
+	Code       string
+	NameRu     string
+	NameEn     string
+	CityRu     string
+	CityEn     string
+	Latitude   float64
+	Longitude  float64
+	Timezone   string
+	DistanceKm float64
+	Bearing    float64
+}
+
 // Данные полета аэропорта
 type AirportFlightData struct {
 	Id int64     
@@ -45,4 +144,31 @@ type AirportFlightData struct {
 	Status string 
 	AirportDepartureCode string 
 	AirportArrivalCode string
-}
\ No newline at end of file
+}
+
+// Данные рейса
+type FlightData struct {
+	Id int64     
+	Code string  
+	PlanDeparture time.Time
+	PlanArrival   time.Time
+	ActualDeparture *time.Time 
+	ActualArrival   *time.Time
+	AircraftCode string 
+	Status string 
+	AirportDepartureCode string 
+	AirportArrivalCode string
+}
+
+// Маршрут между аэропортами по рейсам: координаты концов, модели самолетов и число рейсов
+type RouteData struct {
+	DepartureCode      string
+	ArrivalCode        string
+	DepartureLatitude  float64
+	DepartureLongitude float64
+	ArrivalLatitude    float64
+	ArrivalLongitude   float64
+	AircraftCodes      []string
+	Flights            int
+	DistanceKm         float64
+}
@@ -1,44 +1,139 @@
 package model
 
+import (
+	"time"
+)
+
+// Параметры страницы: смещение (size/offset) или курсор соседней страницы (size/cursor)
+// и способ подсчета общего количества (total)
 type PageInfo struct {
-    Limit  *int `form:"size"`
-    Offset *int `form:"offset"`
+    Limit  *int    `form:"size"`
+    Offset *int    `form:"offset"`
+    Cursor *string `form:"cursor"`
+    TotalMode TotalMode `form:"total"`
 }
 
+// Способ подсчета общего количества записей списка
+type TotalMode string
+
+const (
+    TotalExact     TotalMode = "exact"     // count(*) по фильтру, по умолчанию
+    TotalEstimated TotalMode = "estimated" // оценка планировщика из pg_class или EXPLAIN
+    TotalNone      TotalMode = "none"      // не считать, Total равен 0
+)
+
 type OrderInfo struct {
     Field  string
     Desc bool 
 }
 
+// Параметры сортировки, фильтра и поиска списка, например ?sort=-range,code&filter=range>=3000;nameEn~boeing&q=боинг
+type ListQuery struct {
+    Sort   string `form:"sort"`
+    Filter string `form:"filter"`
+    Q      string `form:"q"`
+}
 
-// Данные по классу мест
+// Условие фильтра списка: поле, операция (=, !=, >, >=, <, <=, ~) и значение
+type FilterInfo struct {
+    Field string
+    Op    string
+    Value string
+}
+
+// Разобранные и проверенные параметры сортировки и фильтра
+type ListOptions struct {
+    Order   []OrderInfo
+    Filters []FilterInfo
+    Codes   []string // коды, найденные поиском; nil - без ограничения
+}
+
+// Параметры поиска по самолетам и аэропортам, например ?q=moskva&type=airport&size=5
+type SearchInput struct {
+    Q     string `form:"q"`
+    Type  string `form:"type"`
+    Limit *int   `form:"size"`
+}
+
+
+// Данные по классу мест. Правила проверки заданы тегами validate, см. util.ValidateInput
 type SeatInput struct {
-    Code     string  `json:"code"`
-    SeatType string  `json:"seatType"`
-    SeatNumb string  `json:"seatNumb"`
+    Code     string  `json:"code" validate:"aircraft_code"`
+    SeatType string  `json:"seatType" validate:"oneof=Business Comfort Economy"`
+    SeatNumb string  `json:"seatNumb" validate:"seat_no"`
 
 }
 
-// Данные названия
-type NameInput struct {
-	En   string  `json:"en"`
-	Ru   string  `json:"ru"`
+// Компоновка салона, например "Business rows 1-5 A,C,D,F; Economy rows 6-30 A-F"
+type LayoutInput struct {
+    Layout string  `json:"layout"`
+    DryRun bool    `json:"dryRun"`
 }
 
-// Общие данные о самолете
+// Общие данные о самолете. Названия на русском и английском обязательны
 type AircraftInput struct {
-	Code     string  `json:"code"`
-	NameRu   string  `json:"nameRu"`
-	NameEn   string  `json:"nameEn"`
-	Range  	 int 	 `json:"range"`
+	Code     string  `json:"code" validate:"aircraft_code"`
+	NameRu   string  `json:"nameRu" validate:"notblank,max=100"`
+	NameEn   string  `json:"nameEn" validate:"notblank,max=100"`
+	Names    map[string]string `json:"names" validate:"dive,keys,lang_tag,endkeys,notblank,max=100"` // названия на других языках
+	Range  	 int 	 `json:"range" validate:"gt=0,lte=20000"`
 }
 
-// Общие данные аэропорта
+// Общие данные аэропорта. Названия и город на русском и английском обязательны
 type AirportInput struct {
-	Code       string 
-	NameRu     string
-	NameEn     string
-	CityRu     string
-	CityEn     string	
-	Timezone   string
-}
\ No newline at end of file
+	Code       string  `json:"code" validate:"airport_code"`
+	NameRu     string  `json:"nameRu" validate:"notblank,max=100"`
+	NameEn     string  `json:"nameEn" validate:"notblank,max=100"`
+	CityRu     string  `json:"cityRu" validate:"notblank,max=100"`
+	CityEn     string  `json:"cityEn" validate:"notblank,max=100"`
+	Names      map[string]string `json:"names" validate:"dive,keys,lang_tag,endkeys,notblank,max=100"`     // названия на других языках
+	CityNames  map[string]string `json:"cityNames" validate:"dive,keys,lang_tag,endkeys,notblank,max=100"` // названия города на других языках
+	Latitude   float64 `json:"latitude" validate:"gte=-90,lte=90"`
+	Longitude  float64 `json:"longitude" validate:"gte=-180,lte=180"`
+	Timezone   string  `json:"timezone" validate:"iana_tz"`
+}
+
+// Параметры поиска аэропортов рядом с точкой: ближайшие size, в радиусе radius (км)
+// или в прямоугольнике bbox=minLon,minLat,maxLon,maxLat
+type GeoInput struct {
+	Latitude  *float64 `form:"lat"`
+	Longitude *float64 `form:"lon"`
+	Radius    *float64 `form:"radius"`
+	BBox      string   `form:"bbox"`
+	Limit     *int     `form:"size"`
+}
+
+// Режим каскадного удаления самолета: места удаляются вместе с самолетом
+const CascadeSeats = "seats"
+
+// Параметры удаления самолета: cascade=seats удаляет места в той же транзакции
+type DeleteAircraftInput struct {
+	Cascade string `form:"cascade"`
+}
+
+// Язык ответа: теги через запятую в порядке предпочтения или all для всех языков
+type LangInput struct {
+	Lang string `form:"lang"`
+}
+
+// Формат ответа списка: json (по умолчанию) или geojson
+type FormatInput struct {
+	Format string `form:"format"`
+}
+
+// Фильтр маршрутов: аэропорт вылета или прилета и модель самолета
+type RouteFilter struct {
+	Airport      *string `form:"airport"`
+	AircraftCode *string `form:"aircraft"`
+}
+
+// Фильтр списка рейсов
+type FlightFilter struct {
+	DepartureAirport *string    `form:"departure"`
+	ArrivalAirport   *string    `form:"arrival"`
+	Status           *string    `form:"status"`
+	AircraftCode     *string    `form:"aircraftCode"`
+	FlightNo         *string    `form:"flightNo"`
+	DepartureFrom    *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	DepartureTo      *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+}
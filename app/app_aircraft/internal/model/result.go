@@ -1,9 +1,11 @@
 package model
 
-// Сообщение валидации сервиса
+// Сообщение валидации сервиса. Code - ключ сообщения в каталоге, Args - параметры сообщения для перевода
 type Validation struct {
     Property string
     Message string
+    Code string
+    Args []any `json:"-"`
 }
 
 // Результат сервиса, данные или валидация
@@ -23,16 +25,33 @@ type ServiceListResult[TD any] struct {
 	Code *string
 	Total int
 	Items *[]TD
+	NextCursor *string
+	PrevCursor *string
+	TotalMode TotalMode
 }
 
-// Результат для передачи даных через канал
-type ChannelItemResult[TD any] struct {
-	Item  *TD
-	Error error
+// Результат чтения страницы: курсоры соседних страниц и способ подсчета Total
+type PageResult struct {
+	NextCursor *string
+	PrevCursor *string
+	TotalMode TotalMode
 }
 
-// Результат для передачи даных через канал
-type ChannelListResult[TD any] struct {
-	Items *[]TD
-	Error error
-}
\ No newline at end of file
+// Описание ошибки в формате RFC 7807 (application/problem+json).
+// Code (ключ сообщения) и Errors (ошибки полей) - члены расширения
+type ProblemDetails struct {
+	Type     string         `json:"type"`
+	Title    string         `json:"title"`
+	Status   int            `json:"status"`
+	Detail   string         `json:"detail,omitempty"`
+	Instance string         `json:"instance,omitempty"`
+	Code     string         `json:"code,omitempty"`
+	Errors   []ProblemField `json:"errors,omitempty"`
+}
+
+// Ошибка поля в описании ошибки RFC 7807
+type ProblemField struct {
+	Property string `json:"property,omitempty"`
+	Message  string `json:"message"`
+	Code     string `json:"code,omitempty"`
+}
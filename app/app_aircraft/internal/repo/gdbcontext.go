@@ -1,17 +1,20 @@
 package repo
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/schema"
 
 	"github.com/jackc/pgx/pgtype"
 	"github.com/snpavlov/app_aircraft/internal/conf"
 	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/geo"
 	"github.com/snpavlov/app_aircraft/internal/model"
 	"github.com/snpavlov/app_aircraft/internal/util"
 )
@@ -19,7 +22,8 @@ import (
 var (
 	airschema = "bookings"
 
-	airportFlightsQuery = `select * from 
+	// В шаблон подставляются только плейсхолдеры построителя, значения передаются аргументами
+	airportFlightsTemplate = `select * from 
 		(select fl.* 
 		, 'departure' as source
 		, ROW_NUMBER() OVER (PARTITION BY departure_airport ORDER BY actual_departure DESC) AS rownum
@@ -34,22 +38,58 @@ var (
 		where arrival_airport in (%s)
 		and actual_arrival is not null
 		) fla
-		where fla.rownum <= ?`
+		where fla.rownum <= %s`
+
+	// Число последних полетов из аэропорта и в аэропорт
+	airportFlightsLimit = 10
+
 )
 
+// Поля списка аэропортов, доступные для сортировки и фильтра
+var AirportListFields = util.ListFields{
+	"code":     {Column: "airport_code"},
+	"nameRu":   {Column: "airport_name->>'ru'"},
+	"nameEn":   {Column: "airport_name->>'en'"},
+	"cityRu":   {Column: "city->>'ru'"},
+	"cityEn":   {Column: "city->>'en'"},
+	"timezone": {Column: "timezone"},
+}
+
 type GormDBContext struct {
 	Configuration conf.IConfiguration
 	GormDb *gorm.DB
+	inTx bool // GormDb связан с транзакцией единицы работы
 }
 
 // Определяем интерфейс репозитория IAirportRepo
 type IAirportRepo interface {
-	GetAitportItems(pager model.PageInfo) ([]model.AirportData, int, error)
-	GetAitportItemByCode(code string) (*model.AirportData, error)
-	GetAitportExistsByCode(code string) (bool, error)
-	// CreateAircraft(input model.AirportInput) (*model.AirportData, error) 
-	// UpdateAircraft(input model.AirportInput) (*model.AirportData, error) 
-	// DeleteAircraft(code string) (*string, error) 
+	GetAitportItems(ctx context.Context, pager model.PageInfo, options model.ListOptions) ([]model.AirportData, int, model.PageResult, error)
+	GetAitportItemByCode(ctx context.Context, code string) (*model.AirportData, error)
+	GetAitportExistsByCode(ctx context.Context, code string) (bool, error)
+	GetAirportNames(ctx context.Context) ([]model.AirportData, error)
+	GetAirportsInBox(ctx context.Context, box geo.BBox) ([]model.AirportData, error)
+	Close() error
+	GetAirportFlightsCount(ctx context.Context, code string) (int64, error)
+	GetAirportForUpdate(ctx context.Context, code string) (*model.AirportData, error)
+	CreateAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error) 
+	UpdateAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error) 
+	ReplaceAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error)
+	DeleteAirport(ctx context.Context, code string) (*string, error) 
+	UnitOfWork() (IUnitOfWork[IAirportRepo], error)
+}
+
+// NewGormDBContext открывает подключение gorm и его пул соединений один раз при запуске.
+// Контекст передается по значению, все копии используют один и тот же пул.
+func (dctx GormDBContext) NewGormDBContext(config conf.IConfiguration) (GormDBContext, error) {
+
+	dctx.Configuration = config
+
+	err := dctx.Connect()
+	if err != nil {
+		return dctx, err
+	}
+
+	return dctx, nil
 }
 
 func (dctx GormDBContext) Open(connection string, dbschema string) (*gorm.DB, error) {
@@ -57,15 +97,22 @@ func (dctx GormDBContext) Open(connection string, dbschema string) (*gorm.DB, er
 			NamingStrategy: schema.NamingStrategy{ 
 				TablePrefix: dbschema + ".",
 			},
+			// Доступность базы проверяется при выполнении запросов, а не при запуске
+			DisableAutomaticPing: true,
 	})
 	if err != nil {
-		return nil, fmt.Errorf("can't open database! Error: %v", err)
+		return nil, fmt.Errorf("can't open database! Error: %w", err)
 	}
 
 	return db, nil
 }
 
+// Connect открывает подключение, если оно еще не открыто
 func (dctx *GormDBContext) Connect() error {
+
+	if dctx.GormDb != nil {
+		return nil
+	}
 	
 	// Формирование строки подключения
 	pgsqlConn, err := dctx.Configuration.GetGormConnectionString()
@@ -73,164 +120,244 @@ func (dctx *GormDBContext) Connect() error {
 		return fmt.Errorf("ошибка получения строки подключения базы данных: %w", err)
 	}
 
+	settings, err := dctx.Configuration.GetPoolSettings()
+	if err != nil {
+		return fmt.Errorf("ошибка получения параметров пула соединений: %w", err)
+	}
+
 	db, err := dctx.Open(pgsqlConn, airschema)
 	
 	if err != nil {
 		return fmt.Errorf("ошибка подключения к базы данных: %w", err)
 	}
 
+	sqlDb, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("ошибка получения пула соединений: %w", err)
+	}
+
+	applyPoolSettings(sqlDb, settings)
+
 	dctx.GormDb = db
 	
 	return nil
 }
 
-func (dctx GormDBContext) GetAitportItems(pager model.PageInfo) ([]model.AirportData, int, error) {
+// Close закрывает пул соединений контекста
+func (dctx GormDBContext) Close() error {
+
+	if dctx.GormDb == nil {
+		return nil
+	}
+
+	sqlDb, err := dctx.GormDb.DB()
+	if err != nil {
+		return fmt.Errorf("ошибка получения пула соединений: %w", err)
+	}
+
+	return sqlDb.Close()
+}
+
+// UnitOfWork возвращает единицу работы, в которой методы репозитория выполняются в одной транзакции gorm
+func (dctx GormDBContext) UnitOfWork() (IUnitOfWork[IAirportRepo], error) {
+	err := dctx.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	return GormUnitOfWork[IAirportRepo]{DB: dctx.GormDb, Bind: func(tx *gorm.DB) IAirportRepo {
+		bound := dctx
+		bound.GormDb = tx
+		bound.inTx = true
+		return bound
+	}}, nil
+}
+
+// queryLimit возвращает число одновременных запросов: в транзакции запросы выполняются
+// по одному, потому что у транзакции одно соединение
+func (dctx GormDBContext) queryLimit() int {
+	if dctx.inTx {
+		return 1
+	}
+	return maxParallelQueries
+}
+
+func (dctx GormDBContext) GetAitportItems(ctx context.Context, pager model.PageInfo, options model.ListOptions) ([]model.AirportData, int, model.PageResult, error) {
 	err := dctx.Connect();
     if err != nil {
-        return nil, 0,  err
+        return nil, 0, model.PageResult{}, err
     }
 
-	totalChan := executeGormItemQueryAsync(dctx.GormDb, 
-	func(gdb *gorm.DB) (int64, error) {
-		var totalCount int64
-		result := dctx.GormDb.Model(&domain.GAirport{}).Count(&totalCount)
+	// Условия фильтра и сортировка проверяются по белому списку полей
+	conditions, args, err := util.NewQueryBuilder("").
+		Placeholders(util.PlaceholderQuestion).
+		Filter(options.Filters, AirportListFields).
+		Codes("airport_code", options.Codes).
+		Conditions()
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
 
-		return  totalCount, result.Error
-	})
+	page, err := newKeysetPage(pager, stableOrder(options.Order, "code"), AirportListFields)
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
 
-	airportsChan := executeGormListQueryAsync(dctx.GormDb, 
-		func(gdb *gorm.DB) ([]domain.GAirport, error) {
-			var airports []domain.GAirport // Declare a slice to hold the results
+	order, err := util.OrderClause(page.query, AirportListFields)
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
 
-			if pager.Offset != nil {
-				gdb = gdb.Offset(*pager.Offset)
-			}
+	// Граница страницы по курсору, не влияет на общее количество
+	keyset, keysetArgs, err := util.NewQueryBuilder("").
+		Placeholders(util.PlaceholderQuestion).
+		Keyset(page.cursor, page.query, AirportListFields).
+		Conditions()
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
 
-			if pager.Limit != nil {
-				gdb = gdb.Limit(*pager.Limit)
-			}
+	// applyFilter добавляет условия фильтра к запросу
+	applyFilter := func(gdb *gorm.DB) *gorm.DB {
+		if conditions == "" {
+			return gdb
+		}
+		return gdb.Where(conditions, args...)
+	}
 
-			result := gdb.
-				Order("airport_code").
-				Find(&airports) // Execute the query
+	var totalCount int
+	var airports []domain.GAirport
+	var airflights []domain.GFlight
 
-			return airports, result.Error
-		})
+	executor := newQueryExecutor(ctx, dctx.queryLimit())
 
-	airportsRes := <- airportsChan
+	executor.Go(func(ctx context.Context) error {
+		var err error
+		totalCount, err = dctx.countRows(ctx, page.total, "bookings.airports_data", conditions != "",
+			applyFilter(dctx.GormDb.WithContext(ctx).Model(&domain.GAirport{})))
+		if err != nil {
+			return fmt.Errorf("ошибка получения количества записей Airport: %w", err)
+		}
+		return nil
+	})
 
-    if airportsRes.Error != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса получения списка Airport: %w", airportsRes.Error)
-	}
+	// Запрос страницы аэропортов и затем полетов из них
+	executor.Go(func(ctx context.Context) error {
+		gdb := applyFilter(dctx.GormDb.WithContext(ctx))
 
-    // Собрать коды в массив
-	codes := util.Map(*airportsRes.Items, func(p domain.GAirport) string {
-		return p.Code
-	})
-	// Сформировать список значений для IN
-	inclause := util.GetInClauseItemsString(codes)
-	// Сформировать запрос получения полетов из аэропорта
-	flightsQuery := fmt.Sprintf(airportFlightsQuery, inclause, inclause)
+		if keyset != "" {
+			gdb = gdb.Where(keyset, keysetArgs...)
+		}
 
-	airflightChan := executeGormListQueryAsync(dctx.GormDb, 
-	func(gdb *gorm.DB) ([]domain.GFlight, error) {
-		var airflights []domain.GFlight // Declare a slice to hold the results
+		if page.pager.Offset != nil {
+			gdb = gdb.Offset(*page.pager.Offset)
+		}
+
+		if page.pager.Limit != nil {
+			gdb = gdb.Limit(*page.pager.Limit)
+		}
 
 		result := gdb.
-			Raw(flightsQuery, 10).Find(&airflights) // Execute the query
+			Order(order).
+			Find(&airports) // Execute the query
 
-		return airflights, result.Error
-	})
+		if result.Error != nil {
+			return fmt.Errorf("ошибка запроса получения списка Airport: %w", result.Error)
+		}
+
+		// Собрать коды в массив
+		codes := util.Map(airports, func(p domain.GAirport) string {
+			return p.Code
+		})
+		// Сформировать запрос получения полетов из аэропорта
+		flightsQuery, flightsArgs := airportFlightsQuery(codes, airportFlightsLimit)
 
-	airflightRes := <- airflightChan
+		result = dctx.GormDb.WithContext(ctx).
+			Raw(flightsQuery, flightsArgs...).Find(&airflights) // Execute the query
+
+		if result.Error != nil {
+			return fmt.Errorf("ошибка запроса получения списка полетов для Airport: %w", result.Error)
+		}
+		return nil
+	})
 
-    if airflightRes.Error != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса получения списка полетов для Airport: %w", airflightRes.Error)
+	if err := executor.Wait(); err != nil {
+		return nil, 0, model.PageResult{}, err
 	}
 
-	fldeparture := util.Filter(*airflightRes.Items, func(fl domain.GFlight) bool {
+	fldeparture := util.Filter(airflights, func(fl domain.GFlight) bool {
 		return  *fl.Source == "departure"
 	})
 
-	flarrival := util.Filter(*airflightRes.Items, func(fl domain.GFlight) bool {
+	flarrival := util.Filter(airflights, func(fl domain.GFlight) bool {
 		return  *fl.Source == "arrival"
 	})
 
-	totalRes := <- totalChan
-
-	if totalRes.Error != nil {
-		return nil, 0, fmt.Errorf("ошибка получения количества записей Airport: %w", airportsRes.Error)
-	}
-
 	// Соединяем результаты основного запроса самолетов и данных их мест
-    airportItems, err := mapAirportData(*airportsRes.Items, fldeparture, flarrival)
+    airportItems, err := mapAirportData(airports, fldeparture, flarrival)
 	if err != nil {
-		return nil, int(*totalRes.Item),  err
+		return nil, totalCount, model.PageResult{}, err
 	}
 
-	return airportItems, int(*totalRes.Item), nil
+	airportItems, pageResult := keysetResult(page, airportItems, airportListKey)
+
+	return airportItems, totalCount, pageResult, nil
 }
 
-func (dctx GormDBContext) GetAitportItemByCode(code string) (*model.AirportData, error) {
+func (dctx GormDBContext) GetAitportItemByCode(ctx context.Context, code string) (*model.AirportData, error) {
 	err := dctx.Connect();
     if err != nil {
         return nil, err
     }
 
-	airportChan := executeGormItemQueryAsync(dctx.GormDb, 
-		func(gdb *gorm.DB) (domain.GAirport, error) {
-			var airport domain.GAirport
+	var airport domain.GAirport
+	var airflights []domain.GFlight
 
-			result := dctx.GormDb.
-				Where("airport_code = ?", code).
-				Assign(domain.GAirport{Code:""}).
-				FirstOrInit(&airport) // Execute the query
+	executor := newQueryExecutor(ctx, dctx.queryLimit())
 
-			return airport, result.Error
-		})
+	executor.Go(func(ctx context.Context) error {
+		result := dctx.GormDb.WithContext(ctx).
+			Where("airport_code = ?", code).
+			Assign(domain.GAirport{Code:""}).
+			FirstOrInit(&airport) // Execute the query
 
+		if result.Error != nil {
+			return fmt.Errorf("ошибка запроса получения Airport: %w", result.Error)
+		}
+		return nil
+	})
 
-	// Сформировать список значений для IN
-	inclause := util.GetInClauseItemsString([]string {code})
 	// Сформировать запрос получения полетов из аэропорта
-	flightsQuery := fmt.Sprintf(airportFlightsQuery, inclause, inclause)
-
-	airflightChan := executeGormListQueryAsync(dctx.GormDb, 
-	func(gdb *gorm.DB) ([]domain.GFlight, error) {
-		var airflights []domain.GFlight // Declare a slice to hold the results
+	flightsQuery, flightsArgs := airportFlightsQuery([]string{code}, airportFlightsLimit)
 
-		result := gdb.
-			Raw(flightsQuery, 10).Find(&airflights) // Execute the query
+	executor.Go(func(ctx context.Context) error {
+		result := dctx.GormDb.WithContext(ctx).
+			Raw(flightsQuery, flightsArgs...).Find(&airflights) // Execute the query
 
-		return airflights, result.Error
+		if result.Error != nil {
+			return fmt.Errorf("ошибка запроса получения списка полетов для Airport: %w", result.Error)
+		}
+		return nil
 	})
 
-	airportRes := <- airportChan
-
-	if airportRes.Error != nil {
-		return nil, fmt.Errorf("ошибка запроса получения Airport: %w", err)
+	if err := executor.Wait(); err != nil {
+		return nil, err
 	}
 
-	if airportRes.Item.Code == "" {
+	if airport.Code == "" {
 		return nil, nil
-	}	
-
-	airflightRes := <- airflightChan
-
-    if airflightRes.Error != nil {
-		return nil, fmt.Errorf("ошибка запроса получения списка полетов для Airport: %w", airflightRes.Error)
 	}
 
-	fldeparture := util.Filter(*airflightRes.Items, func(fl domain.GFlight) bool {
+	fldeparture := util.Filter(airflights, func(fl domain.GFlight) bool {
 		return  *fl.Source == "departure"
 	})
 
-	flarrival := util.Filter(*airflightRes.Items, func(fl domain.GFlight) bool {
+	flarrival := util.Filter(airflights, func(fl domain.GFlight) bool {
 		return  *fl.Source == "arrival"
 	})
 
 	// Соединяем результаты основного запроса самолетов и данных их мест
-    airportItem, err := mapAirportItem(*airportRes.Item, fldeparture, flarrival)
+    airportItem, err := mapAirportItem(airport, fldeparture, flarrival)
 	if err != nil {
 		return nil, err
 	}
@@ -238,7 +365,7 @@ func (dctx GormDBContext) GetAitportItemByCode(code string) (*model.AirportData,
 	return &airportItem, nil
 }
 
-func (dctx GormDBContext) GetAitportExistsByCode(code string) (bool, error) {
+func (dctx GormDBContext) GetAitportExistsByCode(ctx context.Context, code string) (bool, error) {
 	err := dctx.Connect();
     if err != nil {
         return false, err
@@ -246,7 +373,7 @@ func (dctx GormDBContext) GetAitportExistsByCode(code string) (bool, error) {
 
 	var airport domain.GAirport
 
-	result := dctx.GormDb.
+	result := dctx.GormDb.WithContext(ctx).
 			Where("airport_code = ?", code).
 			First(&airport) // Execute the query
 
@@ -263,6 +390,257 @@ func (dctx GormDBContext) GetAitportExistsByCode(code string) (bool, error) {
 
 }
 
+// GetAirportForUpdate читает аэропорт без рейсов и блокирует строку до конца транзакции,
+// чтобы версия не изменилась между проверкой и записью
+func (dctx GormDBContext) GetAirportForUpdate(ctx context.Context, code string) (*model.AirportData, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	var airport domain.GAirport
+
+	result := dctx.GormDb.WithContext(ctx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("airport_code = ?", code).
+			First(&airport) // Execute the query
+
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка запроса блокировки Airport: %w", result.Error)
+	}
+
+	airportItem, err := mapAirportItem(airport, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &airportItem, nil
+}
+
+// GetAirportFlightsCount возвращает количество рейсов, ссылающихся на аэропорт
+func (dctx GormDBContext) GetAirportFlightsCount(ctx context.Context, code string) (int64, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return 0, err
+    }
+
+	var count int64
+
+	result := dctx.GormDb.WithContext(ctx).
+			Model(&domain.GFlight{}).
+			Where("departure_airport = ? OR arrival_airport = ?", code, code).
+			Count(&count) // Execute the query
+
+	if result.Error != nil {
+		return 0, fmt.Errorf("ошибка запроса количества рейсов для Airport: %w", result.Error)
+	}
+
+	return count, nil
+}
+
+func (dctx GormDBContext) CreateAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	position := domain.Point{X: input.Longitude, Y: input.Latitude}
+
+	result := dctx.GormDb.WithContext(ctx).
+			Model(&domain.GAirport{}).
+			Create(map[string]any{
+				"airport_code": input.Code,
+				"airport_name": gorm.Expr("?::jsonb", inputNames(input.Names, input.NameRu, input.NameEn)),
+				"city": gorm.Expr("?::jsonb", inputNames(input.CityNames, input.CityRu, input.CityEn)),
+				"coordinates": position,
+				"timezone": input.Timezone,
+			}) // Execute the query
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса CreateAirport: %w", result.Error)
+	}
+
+	return dctx.GetAitportItemByCode(ctx, input.Code)
+}
+
+func (dctx GormDBContext) UpdateAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	position := domain.Point{X: input.Longitude, Y: input.Latitude}
+
+	result := dctx.GormDb.WithContext(ctx).
+			Model(&domain.GAirport{}).
+			Where("airport_code = ?", input.Code).
+			Updates(map[string]any{
+				"airport_name": gorm.Expr("airport_name || ?::jsonb", inputNames(input.Names, input.NameRu, input.NameEn)),
+				"city": gorm.Expr("city || ?::jsonb", inputNames(input.CityNames, input.CityRu, input.CityEn)),
+				"coordinates": position,
+				"timezone": input.Timezone,
+			}) // Execute the query
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса UpdateAirport: %w", result.Error)
+	}
+
+	return dctx.GetAitportItemByCode(ctx, input.Code)
+}
+
+// ReplaceAirport записывает аэропорт целиком: в отличие от UpdateAirport названия и город заменяются,
+// а не дополняются, поэтому языки, которых нет во входных данных, удаляются
+func (dctx GormDBContext) ReplaceAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	position := domain.Point{X: input.Longitude, Y: input.Latitude}
+
+	result := dctx.GormDb.WithContext(ctx).
+			Model(&domain.GAirport{}).
+			Where("airport_code = ?", input.Code).
+			Updates(map[string]any{
+				"airport_name": inputNames(input.Names, input.NameRu, input.NameEn),
+				"city": inputNames(input.CityNames, input.CityRu, input.CityEn),
+				"coordinates": position,
+				"timezone": input.Timezone,
+			}) // Execute the query
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса ReplaceAirport: %w", result.Error)
+	}
+
+	return dctx.GetAitportItemByCode(ctx, input.Code)
+}
+
+func (dctx GormDBContext) DeleteAirport(ctx context.Context, code string) (*string, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	result := dctx.GormDb.WithContext(ctx).
+			Where("airport_code = ?", code).
+			Delete(&domain.GAirport{}) // Execute the query
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса DeleteAirport: %w", result.Error)
+	}
+
+	return &code, nil
+}
+
+// GetAirportNames возвращает коды, названия и города всех аэропортов для поиска без данных о полетах
+func (dctx GormDBContext) GetAirportNames(ctx context.Context) ([]model.AirportData, error) {
+	err := dctx.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	var airports []domain.GAirport
+	result := dctx.GormDb.WithContext(ctx).Find(&airports)
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка запроса получения названий Airport: %w", result.Error)
+	}
+
+	return mapAirportData(airports, nil, nil)
+}
+
+// GetAirportsInBox возвращает аэропорты, координаты которых попадают в прямоугольник, без данных о полетах
+func (dctx GormDBContext) GetAirportsInBox(ctx context.Context, box geo.BBox) ([]model.AirportData, error) {
+	err := dctx.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	// В point PostgreSQL coordinates[0] - долгота, coordinates[1] - широта
+	gdb := dctx.GormDb.WithContext(ctx).
+		Where("coordinates[1] BETWEEN ? AND ?", box.MinLat, box.MaxLat)
+
+	if box.Wraps() {
+		gdb = gdb.Where("(coordinates[0] >= ? OR coordinates[0] <= ?)", box.MinLon, box.MaxLon)
+	} else {
+		gdb = gdb.Where("coordinates[0] BETWEEN ? AND ?", box.MinLon, box.MaxLon)
+	}
+
+	var airports []domain.GAirport
+	result := gdb.Find(&airports)
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка запроса получения Airport в прямоугольнике: %w", result.Error)
+	}
+
+	return mapAirportData(airports, nil, nil)
+}
+
+// countRows возвращает количество строк запроса gorm способом подсчета страницы.
+// Для оценки текст запроса строится без выполнения, а его план запрашивается через пул соединений gorm.
+func (dctx GormDBContext) countRows(ctx context.Context, mode model.TotalMode, table string, filtered bool,
+	query *gorm.DB) (int, error) {
+
+	switch mode {
+	case model.TotalNone:
+		return 0, nil
+	case model.TotalEstimated:
+		sqlDb, err := dctx.GormDb.DB()
+		if err != nil {
+			return 0, fmt.Errorf("ошибка получения пула соединений: %w", err)
+		}
+		selectQuery, args := dryRunSelect(query)
+		return estimateRows(ctx, sqlDb, table, filtered, selectQuery, args)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	return int(total), nil
+}
+
+// dryRunSelect возвращает текст и аргументы запроса gorm без его выполнения
+func dryRunSelect(query *gorm.DB) (string, []any) {
+	stmt := query.Session(&gorm.Session{DryRun: true}).
+		Select("1").
+		Find(&[]map[string]any{}).
+		Statement
+
+	return stmt.SQL.String(), stmt.Vars
+}
+
+// airportListKey возвращает значение поля сортировки аэропорта для курсора страницы
+func airportListKey(item model.AirportData, field string) any {
+	switch field {
+	case "nameRu":
+		return item.NameRu
+	case "nameEn":
+		return item.NameEn
+	case "cityRu":
+		return item.CityRu
+	case "cityEn":
+		return item.CityEn
+	case "timezone":
+		return item.Timezone
+	default:
+		return item.Code
+	}
+}
+
+// airportFlightsQuery строит запрос последних полетов для списка аэропортов
+func airportFlightsQuery(codes []string, limit int) (string, []any) {
+	qb := util.NewQueryBuilder("").Placeholders(util.PlaceholderQuestion)
+
+	departures := qb.Params(util.Args(codes))
+	arrivals := qb.Params(util.Args(codes))
+	rownum := qb.Param(limit)
+
+	return fmt.Sprintf(airportFlightsTemplate, departures, arrivals, rownum), qb.Args()
+}
+
 func mapAirportItem(airport domain.GAirport,
 				fldepartures []domain.GFlight, 
 				flarrivals []domain.GFlight) (model.AirportData, error) {
@@ -286,24 +664,15 @@ func mapAirportData(airports []domain.GAirport,
 	})	
 
     aircraftItems, err := util.Map2(airports, func(p domain.GAirport) (model.AirportData, error) {
-		var aprtname domain.NameLang
-		var cityname domain.NameLang
-
-		err := p.JNames.AssignTo(&aprtname)
-		if err != nil {
-			return model.AirportData{}, fmt.Errorf("ошибка парсинга имен аэропорта: %w", err)
-		}
-
-		err = p.JCityNames.AssignTo(&cityname)
-		if err != nil {
-			return model.AirportData{}, fmt.Errorf("ошибка парсинга имен города: %w", err)
-		}
-
 		item :=  model.AirportData{ Code: p.Code, 
-			NameRu: aprtname.NameRu, 
-			NameEn: aprtname.NameEn, 
-			CityRu: cityname.NameRu,
-			CityEn: cityname.NameEn,
+			NameRu: p.JNames.Get("ru"), 
+			NameEn: p.JNames.Get("en"), 
+			CityRu: p.JCityNames.Get("ru"),
+			CityEn: p.JCityNames.Get("en"),
+			Names: p.JNames.Languages(),
+			CityNames: p.JCityNames.Languages(),
+			Latitude: geo.Lat(p.Position),
+			Longitude: geo.Lon(p.Position),
 			Timezone: p.Timezone,  } 
 
 		departures, exists := fldeparturesMap[item.Code]
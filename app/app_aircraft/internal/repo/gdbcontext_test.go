@@ -1,13 +1,15 @@
 package repo
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	//"gorm.io/gorm"
 
 	"github.com/snpavlov/app_aircraft/internal/conf"
 	"github.com/snpavlov/app_aircraft/internal/model"
-	//"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/domain"
 	"github.com/snpavlov/app_aircraft/internal/util"
 
 )
@@ -22,7 +24,11 @@ func HelperTest_GetAirportRepo() (IAirportRepo, error) {
     }
 
     // создать экземпляр репозитория
-    repo := GormDBContext{Configuration: config};
+    repo, err := GormDBContext{}.NewGormDBContext(config);
+
+    if err != nil {
+        return nil, fmt.Errorf("не удалось подключиться к базе данных: %v", err)
+    }
 
 	return  repo, nil
      
@@ -39,7 +45,7 @@ func TestAirportsQuery(t *testing.T) {
 
 	pager := model.PageInfo{Limit: util.Ptr(10), Offset: util.Ptr(0)}
 
-	airports, total, err := repo.GetAitportItems(pager)
+	airports, total, _, err := repo.GetAitportItems(context.Background(), pager, model.ListOptions{})
 
 	if err != nil {
 		t.Errorf("Ошибка запроса данных 'GetAitportItems': %v", err)
@@ -68,7 +74,7 @@ func TestAirpoertByCodeQuery(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			
-			airport, err := repo.GetAitportItemByCode(tt.code)
+			airport, err := repo.GetAitportItemByCode(context.Background(), tt.code)
 			if err != nil {
 				t.Errorf("Ошибка запроса данных 'GetAitportItemByCode': %v", err)
 			}
@@ -112,7 +118,7 @@ func TestGetExistsByCodeQuery(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			
-			exists, err := repo.GetAitportExistsByCode(tt.code)
+			exists, err := repo.GetAitportExistsByCode(context.Background(), tt.code)
 			if err != nil {
 				t.Errorf("Ошибка запроса данных 'GetAitportExistsByCode': %v", err)
 			}
@@ -125,3 +131,104 @@ func TestGetExistsByCodeQuery(t *testing.T) {
 
 }
 
+
+// TestAirportWriteQuery тестирует создание, изменение и удаление аэропорта
+func TestAirportWriteQuery(t *testing.T) {
+
+	repo, err := HelperTest_GetAirportRepo() 
+	if err != nil {
+		t.Fatalf("TestAirportWriteQuery: не удалось получить репозиторий: %v", err)
+	}
+
+	input := model.AirportInput{ Code: "TST", 
+		NameRu: "Тестовый", NameEn: "Test", 
+		CityRu: "Тест", CityEn: "Test", 
+		Latitude: 55.75, Longitude: 37.61, 
+		Timezone: "Europe/Moscow" }
+
+	airport, err := repo.CreateAirport(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Ошибка создания аэропорта 'CreateAirport': %v", err)
+	}
+
+	t.Logf("Создан аэропорт с кодом '%v'", airport.Code)
+
+	input.NameEn = "Test updated"
+
+	airport, err = repo.UpdateAirport(context.Background(), input)
+	if err != nil {
+		t.Errorf("Ошибка обновления аэропорта 'UpdateAirport': %v", err)
+	} else if airport.NameEn != input.NameEn {
+		t.Errorf("Ожидалось имя '%v', получено '%v'", input.NameEn, airport.NameEn)
+	}
+
+	flights, err := repo.GetAirportFlightsCount(context.Background(), input.Code)
+	if err != nil {
+		t.Errorf("Ошибка запроса данных 'GetAirportFlightsCount': %v", err)
+	} else if flights != 0 {
+		t.Errorf("Для нового аэропорта ожидалось 0 рейсов, получено %v", flights)
+	}
+
+	code, err := repo.DeleteAirport(context.Background(), input.Code)
+	if err != nil {
+		t.Errorf("Ошибка удаления аэропорта 'DeleteAirport': %v", err)
+	}
+
+	t.Logf("Удален аэропорт с кодом '%v'", *code)
+}
+
+// TestAirportFlightsQuery проверяет, что коды аэропортов передаются аргументами, а не текстом запроса
+func TestAirportFlightsQuery(t *testing.T) {
+
+	codes := []string{"SVO", "x') or ('1'='1"}
+	query, args := airportFlightsQuery(codes, 10)
+
+	if strings.Contains(query, codes[1]) {
+		t.Errorf("Значение кода попало в текст запроса: %s", query)
+	}
+
+	if strings.Count(query, "?") != len(args) || len(args) != 5 {
+		t.Errorf("Число плейсхолдеров %d не соответствует аргументам %v", strings.Count(query, "?"), args)
+	}
+}
+
+// TestDryRunSelect проверяет, что запрос для оценки количества строится без обращения к базе данных
+func TestDryRunSelect(t *testing.T) {
+
+	config, err := conf.Configuration{}.New().LoadConfiguration("./../..")
+	if err != nil {
+		t.Fatalf("Не удалось загрузить конфигурацию: %v", err)
+	}
+
+	dctx, err := GormDBContext{}.NewGormDBContext(config)
+	if err != nil {
+		t.Fatalf("Не удалось открыть подключение: %v", err)
+	}
+	defer dctx.Close()
+
+	query, args := dryRunSelect(dctx.GormDb.Model(&domain.GFlight{}).
+		Where("departure_airport = ?", "DME").
+		Where("status = ?", "Arrived"))
+
+	expected := `SELECT 1 FROM "flights" WHERE departure_airport = $1 AND status = $2`
+	if query != expected {
+		t.Errorf("Ожидался запрос '%s', получено '%s'", expected, query)
+	}
+
+	if len(args) != 2 || args[0] != "DME" || args[1] != "Arrived" {
+		t.Errorf("Неверные аргументы %v", args)
+	}
+}
+
+// TestPlanRows проверяет разбор оценки строк из плана EXPLAIN (FORMAT JSON)
+func TestPlanRows(t *testing.T) {
+
+	rows, err := planRows([]byte(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 214867, "Plan Width": 4}}]`))
+	if err != nil || rows != 214867 {
+		t.Errorf("Ожидалось 214867 строк, получено %v: %v", rows, err)
+	}
+
+	if _, err := planRows([]byte(`[]`)); err == nil {
+		t.Errorf("Ожидалась ошибка разбора пустого плана")
+	}
+}
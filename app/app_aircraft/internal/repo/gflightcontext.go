@@ -0,0 +1,266 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jackc/pgx/pgtype"
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/geo"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+var (
+	// Ключ сортировки рейсов: время вылета по расписанию и идентификатор
+	flightOrder = []model.OrderInfo{{Field: "departure"}, {Field: "id"}}
+
+	flightKeyFields = util.ListFields{
+		"departure": {Column: "scheduled_departure", Type: util.FieldTime},
+		"id":        {Column: "flight_id", Type: util.FieldInteger},
+	}
+)
+
+// Определяем интерфейс репозитория IFlightRepo
+type IFlightRepo interface {
+	GetFlightItems(ctx context.Context, filter model.FlightFilter, pager model.PageInfo) ([]model.FlightData, int, model.PageResult, error)
+	GetFlightItemById(ctx context.Context, id int64) (*model.FlightData, error)
+	GetRouteItems(ctx context.Context, filter model.RouteFilter) ([]model.RouteData, error)
+}
+
+// Маршрут рейсов с координатами аэропортов
+type flightRoute struct {
+	DepartureCode      string
+	ArrivalCode        string
+	DepartureLatitude  float64
+	DepartureLongitude float64
+	ArrivalLatitude    float64
+	ArrivalLongitude   float64
+	AircraftCodes      string
+	Flights            int
+}
+
+// GetFlightItems возвращает рейсы по фильтру с пагинацией
+func (dctx GormDBContext) GetFlightItems(ctx context.Context, filter model.FlightFilter, pager model.PageInfo) ([]model.FlightData, int, model.PageResult, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, 0, model.PageResult{}, err
+    }
+
+	page, err := newKeysetPage(pager, flightOrder, flightKeyFields)
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
+
+	order, err := util.OrderClause(page.query, flightKeyFields)
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
+
+	// Граница страницы по курсору, не влияет на общее количество
+	keyset, keysetArgs, err := util.NewQueryBuilder("").
+		Placeholders(util.PlaceholderQuestion).
+		Keyset(page.cursor, page.query, flightKeyFields).
+		Conditions()
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
+
+	var totalCount int
+	var flights []domain.GFlight
+
+	executor := newQueryExecutor(ctx, dctx.queryLimit())
+
+	executor.Go(func(ctx context.Context) error {
+		var err error
+		totalCount, err = dctx.countRows(ctx, page.total, "bookings.flights", filter != (model.FlightFilter{}),
+			applyFlightFilter(dctx.GormDb.WithContext(ctx).Model(&domain.GFlight{}), filter))
+		if err != nil {
+			return fmt.Errorf("ошибка получения количества записей Flight: %w", err)
+		}
+		return nil
+	})
+
+	executor.Go(func(ctx context.Context) error {
+		gdb := applyFlightFilter(dctx.GormDb.WithContext(ctx), filter)
+
+		if keyset != "" {
+			gdb = gdb.Where(keyset, keysetArgs...)
+		}
+
+		if page.pager.Offset != nil {
+			gdb = gdb.Offset(*page.pager.Offset)
+		}
+
+		if page.pager.Limit != nil {
+			gdb = gdb.Limit(*page.pager.Limit)
+		}
+
+		result := gdb.
+			Order(order).
+			Find(&flights) // Execute the query
+
+		if result.Error != nil {
+			return fmt.Errorf("ошибка запроса получения списка Flight: %w", result.Error)
+		}
+		return nil
+	})
+
+	if err := executor.Wait(); err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
+
+	flightItems, pageResult := keysetResult(page, util.Map(flights, mapFlightItem), flightListKey)
+
+	return flightItems, totalCount, pageResult, nil
+}
+
+// GetFlightItemById возвращает рейс по идентификатору
+func (dctx GormDBContext) GetFlightItemById(ctx context.Context, id int64) (*model.FlightData, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	var flight domain.GFlight
+
+	result := dctx.GormDb.WithContext(ctx).
+			Where("flight_id = ?", id).
+			First(&flight) // Execute the query
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("ошибка запроса получения Flight: %w", result.Error)
+	}
+
+	flightItem := mapFlightItem(flight)
+
+	return &flightItem, nil
+}
+
+// GetRouteItems возвращает маршруты (пары аэропортов вылета и прилета) по рейсам с фильтром
+func (dctx GormDBContext) GetRouteItems(ctx context.Context, filter model.RouteFilter) ([]model.RouteData, error) {
+	err := dctx.Connect();
+    if err != nil {
+        return nil, err
+    }
+
+	// В point PostgreSQL coordinates[0] - долгота, coordinates[1] - широта
+	gdb := dctx.GormDb.WithContext(ctx).
+		Table("bookings.flights fl").
+		Select(`fl.departure_airport as departure_code
+			, fl.arrival_airport as arrival_code
+			, dep.coordinates[1] as departure_latitude
+			, dep.coordinates[0] as departure_longitude
+			, arr.coordinates[1] as arrival_latitude
+			, arr.coordinates[0] as arrival_longitude
+			, string_agg(distinct fl.aircraft_code, ',' order by fl.aircraft_code) as aircraft_codes
+			, count(*) as flights`).
+		Joins("join bookings.airports_data dep on dep.airport_code = fl.departure_airport").
+		Joins("join bookings.airports_data arr on arr.airport_code = fl.arrival_airport")
+
+	if filter.Airport != nil {
+		gdb = gdb.Where("(fl.departure_airport = ? or fl.arrival_airport = ?)", *filter.Airport, *filter.Airport)
+	}
+
+	if filter.AircraftCode != nil {
+		gdb = gdb.Where("fl.aircraft_code = ?", *filter.AircraftCode)
+	}
+
+	var routes []flightRoute
+
+	result := gdb.
+		Group("fl.departure_airport, fl.arrival_airport, dep.coordinates[0], dep.coordinates[1], arr.coordinates[0], arr.coordinates[1]").
+		Order("fl.departure_airport, fl.arrival_airport").
+		Scan(&routes) // Execute the query
+
+	if result.Error != nil {
+		return nil, fmt.Errorf("ошибка запроса получения маршрутов Flight: %w", result.Error)
+	}
+
+	return util.Map(routes, mapRouteItem), nil
+}
+
+// applyFlightFilter добавляет к запросу условия фильтра рейсов
+func applyFlightFilter(gdb *gorm.DB, filter model.FlightFilter) *gorm.DB {
+
+	if filter.DepartureAirport != nil {
+		gdb = gdb.Where("departure_airport = ?", *filter.DepartureAirport)
+	}
+
+	if filter.ArrivalAirport != nil {
+		gdb = gdb.Where("arrival_airport = ?", *filter.ArrivalAirport)
+	}
+
+	if filter.Status != nil {
+		gdb = gdb.Where("status = ?", *filter.Status)
+	}
+
+	if filter.AircraftCode != nil {
+		gdb = gdb.Where("aircraft_code = ?", *filter.AircraftCode)
+	}
+
+	if filter.FlightNo != nil {
+		gdb = gdb.Where("flight_no = ?", *filter.FlightNo)
+	}
+
+	if filter.DepartureFrom != nil {
+		gdb = gdb.Where("scheduled_departure >= ?", *filter.DepartureFrom)
+	}
+
+	if filter.DepartureTo != nil {
+		gdb = gdb.Where("scheduled_departure < ?", *filter.DepartureTo)
+	}
+
+	return gdb
+}
+
+// flightListKey возвращает значение поля сортировки рейса для курсора страницы
+func flightListKey(item model.FlightData, field string) any {
+	if field == "departure" {
+		return item.PlanDeparture
+	}
+	return item.Id
+}
+
+func mapFlightItem(p domain.GFlight) model.FlightData {
+	return model.FlightData{Id: p.Id,
+		Code: p.Code,
+		PlanDeparture: p.PlanDeparture.Time,
+		PlanArrival: p.PlanArrival.Time,
+		ActualDeparture: util.PrtOrNil(p.ActualDeparture, func(p pgtype.Timestamptz) *time.Time {
+			return &p.Time
+		}),
+		ActualArrival: util.PrtOrNil(p.ActualArrival, func(p pgtype.Timestamptz) *time.Time {
+			return &p.Time
+		}),
+		AircraftCode: p.AircraftCode,
+		Status: p.Status,
+		AirportDepartureCode: p.AirportDepartureCode,
+		AirportArrivalCode: p.AirportArrivalCode,
+	}
+}
+
+func mapRouteItem(p flightRoute) model.RouteData {
+	departure := geo.NewPoint(p.DepartureLatitude, p.DepartureLongitude)
+	arrival := geo.NewPoint(p.ArrivalLatitude, p.ArrivalLongitude)
+
+	return model.RouteData{
+		DepartureCode: p.DepartureCode,
+		ArrivalCode: p.ArrivalCode,
+		DepartureLatitude: p.DepartureLatitude,
+		DepartureLongitude: p.DepartureLongitude,
+		ArrivalLatitude: p.ArrivalLatitude,
+		ArrivalLongitude: p.ArrivalLongitude,
+		AircraftCodes: strings.Split(p.AircraftCodes, ","),
+		Flights: p.Flights,
+		DistanceKm: geo.Distance(departure, arrival),
+	}
+}
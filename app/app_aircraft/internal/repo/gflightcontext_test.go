@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/snpavlov/app_aircraft/internal/conf"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+func HelperTest_GetFlightRepo() (IFlightRepo, error) {
+
+    // создать экземпляр конфигурации и загрузить данные
+    config, err := conf.Configuration{}.New().LoadConfiguration("./../.."); 
+
+    if err != nil {
+        return nil, err
+    }
+
+	return GormDBContext{}.NewGormDBContext(config)
+}
+
+// TestFlightsQuery
+func TestFlightsQuery(t *testing.T) {
+
+	repo, err := HelperTest_GetFlightRepo() 
+    if err != nil {
+        t.Fatalf("Не удалось получить репозиторий: %v", err)
+    }
+
+	from := time.Date(2017, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	filter := model.FlightFilter{
+		DepartureAirport: util.Ptr("DME"),
+		DepartureFrom: &from,
+		DepartureTo: util.Ptr(from.AddDate(0, 0, 7)),
+	}
+
+	pager := model.PageInfo{Limit: util.Ptr(10), Offset: util.Ptr(0)}
+
+	flights, total, _, err := repo.GetFlightItems(context.Background(), filter, pager)
+	if err != nil {
+		t.Errorf("Ошибка запроса данных 'GetFlightItems': %v", err)
+    }
+
+	for _, fl := range flights {
+		if fl.AirportDepartureCode != "DME" {
+			t.Errorf("Рейс %v не соответствует фильтру аэропорта вылета", fl.Id)
+		}
+	}
+
+	t.Logf("Получено %v элементов из %v", len(flights), total)
+}
+
+// TestFlightByIdQuery
+func TestFlightByIdQuery(t *testing.T) {
+
+	repo, err := HelperTest_GetFlightRepo() 
+    if err != nil {
+        t.Fatalf("Не удалось получить репозиторий: %v", err)
+    }
+
+	flight, err := repo.GetFlightItemById(context.Background(), -1)
+	if err != nil {
+		t.Errorf("Ошибка запроса данных 'GetFlightItemById': %v", err)
+    }
+
+	if flight != nil {
+		t.Errorf("Для несуществующего идентификатора ожидался nil")
+	}
+}
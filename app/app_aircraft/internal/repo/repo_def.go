@@ -1,16 +1,12 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
+	"github.com/snpavlov/app_aircraft/internal/domain"
 	"github.com/snpavlov/app_aircraft/internal/model"
 )
 
-// Названия типа JSONB
-type NameLang struct {
-    NameRu string `json:"ru"`
-    NameEn string `json:"en"`
-}
-
 type Total struct {
 	Total  	 int 	 `db:"Total"`
 }
@@ -21,11 +17,16 @@ type Exists struct {
 
 type Aircraft struct {
 	Code     string  `db:"Code"`
-	NameRu   string  `db:"NameRu"`
-	NameEn   string  `db:"NameEn"`
+	Names    domain.LocalizedText `db:"Names"`
 	Range  	 int 	 `db:"range"`
 }
 
+type Seat struct {
+	Code      string  `db:"Code"`
+	SeatNo    string  `db:"SeatNo"`
+	SeatType  string  `db:"SeatType"`
+}
+
 type SeatType struct {
 	Code      string  `db:"Code"`
 	SeatType  string  `db:"SeatType"`
@@ -35,13 +36,27 @@ type SeatType struct {
 // Определяем интерфейс репозитория IAircraftRepo
 type IAircraftRepo interface {
 	GetDBConnection() (*sql.DB, error)
-	GetAircraftItems(db *sql.DB, pager model.PageInfo) ([]model.AircraftData, int, error)
-	GetAircraftItemByCode(db *sql.DB, code string) (*model.AircraftData, error)
-	GetExistsByCode(db *sql.DB, code string) (bool, error)
-	CreateAircraft(db *sql.DB, input model.AircraftInput) (*model.AircraftData, error) 
-	UpdateAircraft(db *sql.DB, input model.AircraftInput) (*model.AircraftData, error) 
-	DeleteAircraft(db *sql.DB, code string) (*string, error) 
-
-	GetAircraftItemsAsync(db *sql.DB, pager model.PageInfo) ([]model.AircraftData, int, error)
-	GetAircraftItemByCodeAsync(db *sql.DB, code string) (*model.AircraftData, error)
+	UnitOfWork() (IUnitOfWork[DBTX], error)
+	Close() error
+	GetAircraftItems(ctx context.Context, db *sql.DB, pager model.PageInfo, options model.ListOptions) ([]model.AircraftData, int, model.PageResult, error)
+	GetAircraftItemByCode(ctx context.Context, db DBTX, code string) (*model.AircraftData, error)
+	GetExistsByCode(ctx context.Context, db DBTX, code string) (bool, error)
+	CreateAircraft(ctx context.Context, db DBTX, input model.AircraftInput) (*model.AircraftData, error) 
+	UpdateAircraft(ctx context.Context, db DBTX, input model.AircraftInput) (*model.AircraftData, error) 
+	ReplaceAircraft(ctx context.Context, db DBTX, input model.AircraftInput) (*model.AircraftData, error)
+	DeleteAircraft(ctx context.Context, db DBTX, code string) (*string, error) 
+	GetAircraftDependencies(ctx context.Context, db DBTX, code string) (*model.AircraftDependencies, error)
+	GetAircraftForUpdate(ctx context.Context, db DBTX, code string) (*model.AircraftData, error)
+
+	GetAircraftItemsAsync(ctx context.Context, db *sql.DB, pager model.PageInfo, options model.ListOptions) ([]model.AircraftData, int, model.PageResult, error)
+	GetAircraftItemByCodeAsync(ctx context.Context, db *sql.DB, code string) (*model.AircraftData, error)
+	GetAircraftNames(ctx context.Context, db *sql.DB) ([]model.AircraftData, error)
+
+	GetSeatItems(ctx context.Context, db DBTX, code string) (*model.AircraftSeatsData, error)
+	GetSeatExists(ctx context.Context, db DBTX, code string, seatNo string) (bool, error)
+	CreateSeat(ctx context.Context, db DBTX, input model.SeatInput) (*model.AircraftSeatsData, error)
+	UpdateSeat(ctx context.Context, db DBTX, input model.SeatInput) (*model.AircraftSeatsData, error)
+	DeleteSeat(ctx context.Context, db DBTX, code string, seatNo string) (*model.AircraftSeatsData, error)
+	DeleteAircraftSeats(ctx context.Context, db DBTX, code string) (int64, error)
+	ReplaceSeatLayout(ctx context.Context, db DBTX, code string, seats []model.SeatItemData) (*model.AircraftSeatsData, error)
 }
\ No newline at end of file
@@ -0,0 +1,44 @@
+package repo
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Максимальное число одновременно выполняемых запросов одной группы
+const maxParallelQueries = 4
+
+// queryExecutor выполняет группу связанных запросов параллельно.
+// Первая ошибка отменяет контекст остальных запросов группы и возвращается из Wait,
+// число одновременно выполняемых запросов ограничено.
+type queryExecutor struct {
+	group *errgroup.Group
+	ctx   context.Context
+}
+
+// newQueryExecutor создает группу запросов, производную от контекста вызова
+func newQueryExecutor(ctx context.Context, limit int) *queryExecutor {
+	group, gctx := errgroup.WithContext(ctx)
+	if limit > 0 {
+		group.SetLimit(limit)
+	}
+	return &queryExecutor{group: group, ctx: gctx}
+}
+
+// Go запускает запрос с контекстом группы. Запрос не выполняется,
+// если группа уже отменена ошибкой другого запроса.
+// Go нельзя вызывать из запроса той же группы: при исчерпании лимита это приведет к блокировке.
+func (qe *queryExecutor) Go(queryFn func(ctx context.Context) error) {
+	qe.group.Go(func() error {
+		if err := qe.ctx.Err(); err != nil {
+			return err
+		}
+		return queryFn(qe.ctx)
+	})
+}
+
+// Wait ожидает завершения всех запросов группы и возвращает первую ошибку
+func (qe *queryExecutor) Wait() error {
+	return qe.group.Wait()
+}
@@ -0,0 +1,269 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// fakeQuery описывает ответ тестового драйвера на запрос, содержащий Match
+type fakeQuery struct {
+	Match   string
+	Delay   time.Duration
+	Err     error
+	Columns []string
+	Rows    [][]driver.Value
+}
+
+// fakeConnector - тестовый драйвер database/sql, отвечающий по сценарию без базы данных
+type fakeConnector struct {
+	queries   []fakeQuery
+	active    atomic.Int32
+	maxActive atomic.Int32
+	commits   atomic.Int32
+	rollbacks atomic.Int32
+	conns     atomic.Int32
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.conns.Add(1)
+	return &fakeConn{connector: c}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return fakeDriver{}
+}
+
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) {
+	return nil, errors.New("fake driver: используйте sql.OpenDB")
+}
+
+type fakeConn struct {
+	connector *fakeConnector
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("fake driver: prepare не поддерживается")
+}
+
+func (c *fakeConn) Close() error { return nil }
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return fakeTx{connector: c.connector}, nil
+}
+
+// fakeTx считает фиксации и откаты транзакций тестового драйвера
+type fakeTx struct {
+	connector *fakeConnector
+}
+
+func (tx fakeTx) Commit() error {
+	tx.connector.commits.Add(1)
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.connector.rollbacks.Add(1)
+	return nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	active := c.connector.active.Add(1)
+	defer c.connector.active.Add(-1)
+	for {
+		max := c.connector.maxActive.Load()
+		if active <= max || c.connector.maxActive.CompareAndSwap(max, active) {
+			break
+		}
+	}
+
+	for _, q := range c.connector.queries {
+		if !strings.Contains(query, q.Match) {
+			continue
+		}
+		if q.Delay > 0 {
+			select {
+			case <-time.After(q.Delay):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		if q.Err != nil {
+			return nil, q.Err
+		}
+		return &fakeRows{columns: q.Columns, rows: q.Rows}, nil
+	}
+	return nil, errors.New("fake driver: неизвестный запрос")
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func newFakeDB(t *testing.T, queries ...fakeQuery) (*sql.DB, *fakeConnector) {
+	connector := &fakeConnector{queries: queries}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { db.Close() })
+	return db, connector
+}
+
+var (
+	fakeTotal = fakeQuery{Match: `count(*) as "Total"`,
+		Columns: []string{"Total"}, Rows: [][]driver.Value{{int64(2)}}}
+	fakeSeatTypes = fakeQuery{Match: "from bookings.seats",
+		Columns: []string{"Code", "SeatType", "SeatCount"},
+		Rows:    [][]driver.Value{{"319", "Business", int64(20)}, {"319", "Economy", int64(96)}}}
+	fakeAircrafts = fakeQuery{Match: "from bookings.aircrafts_data",
+		Columns: []string{"Code", "Names", "range"},
+		Rows: [][]driver.Value{
+			{"319", []byte(`{"ru": "Аэробус A319-100", "en": "Airbus A319-100"}`), int64(6700)},
+			{"320", []byte(`{"ru": "Аэробус A320-200", "en": "Airbus A320-200", "de": "Airbus A320-200"}`), int64(5700)},
+		}}
+)
+
+// TestGetAircraftItemsAsyncFake проверяет сборку результата параллельных запросов
+func TestGetAircraftItemsAsyncFake(t *testing.T) {
+	db, _ := newFakeDB(t, fakeTotal, fakeSeatTypes, fakeAircrafts)
+
+	items, total, _, err := AircraftSqlRepo{}.GetAircraftItemsAsync(context.Background(), db, model.PageInfo{}, model.ListOptions{})
+	if err != nil {
+		t.Fatalf("Ошибка получения списка самолетов: %v", err)
+	}
+
+	if total != 2 || len(items) != 2 {
+		t.Fatalf("Ожидалось 2 самолета, получено total=%d, items=%d", total, len(items))
+	}
+
+	if items[0].SeatCount != 116 || items[1].Seats != nil {
+		t.Errorf("Неверное соединение мест: %+v", items)
+	}
+
+	// Названия на всех языках читаются из JSONB без изменения структур
+	if items[1].NameRu != "Аэробус A320-200" || items[1].Names["de"] != "Airbus A320-200" {
+		t.Errorf("Неверные названия: %+v", items[1])
+	}
+}
+
+// TestGetAircraftItemByCodeAsyncFakeNotFound проверяет, что отсутствующий самолет возвращается как nil без ошибки
+func TestGetAircraftItemByCodeAsyncFakeNotFound(t *testing.T) {
+	db, _ := newFakeDB(t, fakeSeatTypes,
+		fakeQuery{Match: "from bookings.aircrafts_data", Columns: fakeAircrafts.Columns})
+
+	item, err := AircraftSqlRepo{}.GetAircraftItemByCodeAsync(context.Background(), db, "000")
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if item != nil {
+		t.Errorf("Ожидался nil, получено %+v", item)
+	}
+}
+
+// TestQueryExecutorErrors проверяет отмену запросов группы при первой ошибке
+func TestQueryExecutorErrors(t *testing.T) {
+	errQuery := errors.New("query failed")
+
+	tests := []struct {
+		name    string
+		queries []fakeQuery
+		wantErr error
+	}{
+		{
+			name: "Ошибка Total отменяет медленный запрос самолетов",
+			queries: []fakeQuery{
+				{Match: `count(*) as "Total"`, Err: errQuery},
+				fakeSeatTypes,
+				{Match: "from bookings.aircrafts_data", Delay: 10 * time.Second, Columns: fakeAircrafts.Columns},
+			},
+			wantErr: errQuery,
+		},
+		{
+			name: "Ошибка самолетов отменяет медленный Total",
+			queries: []fakeQuery{
+				{Match: `count(*) as "Total"`, Delay: 10 * time.Second, Columns: fakeTotal.Columns},
+				fakeSeatTypes,
+				{Match: "from bookings.aircrafts_data", Err: errQuery},
+			},
+			wantErr: errQuery,
+		},
+		{
+			name: "Ошибка сканирования не блокирует ожидание",
+			queries: []fakeQuery{
+				fakeTotal,
+				fakeSeatTypes,
+				{Match: "from bookings.aircrafts_data", Columns: []string{"Code"}, Rows: [][]driver.Value{{"319"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, _ := newFakeDB(t, tt.queries...)
+
+			start := time.Now()
+			_, _, _, err := AircraftSqlRepo{}.GetAircraftItemsAsync(context.Background(), db, model.PageInfo{}, model.ListOptions{})
+			if err == nil {
+				t.Fatal("Ожидалась ошибка")
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", tt.wantErr, err)
+			}
+
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Запросы группы не были отменены, ожидание %v", elapsed)
+			}
+		})
+	}
+}
+
+// TestQueryExecutorLimit проверяет ограничение числа одновременных запросов
+func TestQueryExecutorLimit(t *testing.T) {
+	db, connector := newFakeDB(t, fakeQuery{Match: "select", Delay: 20 * time.Millisecond,
+		Columns: []string{"n"}, Rows: [][]driver.Value{{int64(1)}}})
+
+	executor := newQueryExecutor(context.Background(), 2)
+	for i := 0; i < 8; i++ {
+		executor.Go(func(ctx context.Context) error {
+			_, err := executeRowsQuery(ctx, db, "select 1", nil, func(rows *sql.Rows) (int, error) {
+				var n int
+				err := rows.Scan(&n)
+				return n, err
+			})
+			return err
+		})
+	}
+
+	if err := executor.Wait(); err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if max := connector.maxActive.Load(); max > 2 {
+		t.Errorf("Одновременно выполнялось %d запросов, ожидалось не более 2", max)
+	}
+}
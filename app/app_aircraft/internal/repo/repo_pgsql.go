@@ -1,8 +1,9 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
@@ -12,11 +13,18 @@ import (
 	"github.com/snpavlov/app_aircraft/internal/util"
 )
 
+// Поля списка самолетов, доступные для сортировки и фильтра
+var AircraftListFields = util.ListFields{
+	"code":   {Column: "aircraft_code"},
+	"nameRu": {Column: "model->>'ru'"},
+	"nameEn": {Column: "model->>'en'"},
+	"range":  {Column: "range", Type: util.FieldInteger},
+}
+
 var (
 	queryAircrafts = `select 
 		aircraft_code as "Code"
-		, model->>'ru' as "NameRu"
-		, model->>'en' as "NameEn"
+		, model as "Names"
 		, range 
 		from bookings.aircrafts_data`
 	querySeatTypes = `select 
@@ -26,61 +34,122 @@ var (
         from bookings.seats st`
 	queryTotal = `select count(*) as "Total" from bookings.aircrafts_data`
 
+	// Поля количества мест для сортировки по псевдонимам запроса
+	seatTypeOrderColumns = util.ListFields{
+		"code":     {Column: `"Code"`},
+		"seatType": {Column: `"SeatType"`},
+	}
+
 	createAircraft = `insert into bookings.aircrafts_data ("aircraft_code", "model", "range") values ($1, $2, $3)`
 	updateAircraft = `update bookings.aircrafts_data set
-						"model" = "model"
- 									|| jsonb_build_object('en', $2::varchar)
- 									|| jsonb_build_object('ru', $3::varchar)
- 						, "range" = $4
+						"model" = "model" || $2::jsonb
+ 						, "range" = $3
+ 						where "aircraft_code" = $1`
+	replaceAircraft = `update bookings.aircrafts_data set
+						"model" = $2::jsonb
+ 						, "range" = $3
  						where "aircraft_code" = $1`
 	deleteAircraft = `delete from bookings.aircrafts_data where "aircraft_code" = $1`
 
 	isExistsAircraft = `SELECT EXISTS (SELECT 1 FROM bookings.aircrafts_data WHERE "aircraft_code" = $1);`
+
+	// Рейс считается будущим, пока не наступило время вылета по расписанию
+	queryAircraftDependencies = `select
+		(select count(*) from bookings.seats where aircraft_code = $1) as "Seats"
+		, count(*) filter (where fl.scheduled_departure > now()) as "FutureFlights"
+		, count(*) filter (where fl.scheduled_departure <= now()) as "PastFlights"
+		from bookings.flights fl
+		where fl.aircraft_code = $1`
 	
 )
 
 type AircraftSqlRepo struct {
 	Configuration conf.IConfiguration
+	DB *sql.DB
 }
 
-// GetDBConnection возвращает подключение к PostgreSQL и ошибку.
-// Параметры подключения лучше вынести в конфигурацию (например, через флаги или переменные окружения).
-func (repo AircraftSqlRepo) GetDBConnection() (*sql.DB, error) {
+// NewAircraftSqlRepo открывает пул соединений с PostgreSQL, общий для всех запросов репозитория.
+// Параметры подключения и пула берутся из конфигурации.
+func (repo AircraftSqlRepo) NewAircraftSqlRepo(config conf.IConfiguration) (AircraftSqlRepo, error) {
+
+	repo.Configuration = config
 
 	// Формирование строки подключения
-	pgsqlConn, err := repo.Configuration.GetPgsqlConnectionString()
+	pgsqlConn, err := config.GetPgsqlConnectionString()
+	if err != nil {
+		return repo, fmt.Errorf("ошибка получения строки подключения базы данных: %w", err)
+	}
+
+	settings, err := config.GetPoolSettings()
 	if err != nil {
-		return nil, fmt.Errorf("ошибка получения строки подключения базы данных: %w", err)
+		return repo, fmt.Errorf("ошибка получения параметров пула соединений: %w", err)
 	}
 
 	// Открытие подключения (пула соединений)
 	db, err := sql.Open("pgx", pgsqlConn)
 	if err != nil {
-		return nil, fmt.Errorf("ошибка при открытии базы данных: %w", err)
+		return repo, fmt.Errorf("ошибка при открытии базы данных: %w", err)
 	}
 
-	// Опционально: конфигурация пула соединений
-	//db.SetMaxOpenConns(25) // Максимум открытых соединений
-	//db.SetMaxIdleConns(25) // Максимум соединений в режиме ожидания
-	//db.SetConnMaxLifetime(0) // Время жизни соединения (0 = бессрочно)
+	applyPoolSettings(db, settings)
+
+	repo.DB = db
 
 	log.Println("Успешное подключение к базе данных!")
-	return db, nil
+	return repo, nil
+}
+
+// GetDBConnection возвращает общий пул соединений с PostgreSQL.
+// Закрывать его должен владелец репозитория через Close.
+func (repo AircraftSqlRepo) GetDBConnection() (*sql.DB, error) {
+
+	if repo.DB == nil {
+		return nil, fmt.Errorf("пул соединений базы данных не открыт")
+	}
+
+	return repo.DB, nil
+}
+
+// UnitOfWork возвращает единицу работы над общим пулом соединений
+func (repo AircraftSqlRepo) UnitOfWork() (IUnitOfWork[DBTX], error) {
+
+	db, err := repo.GetDBConnection()
+	if err != nil {
+		return nil, err
+	}
+
+	return SqlUnitOfWork{DB: db}, nil
+}
+
+// Close закрывает пул соединений репозитория
+func (repo AircraftSqlRepo) Close() error {
+
+	if repo.DB == nil {
+		return nil
+	}
+
+	return repo.DB.Close()
 }
 
 // GetAircraftItems возвращает самолеты с пагинацией
-func (repo AircraftSqlRepo) GetAircraftItems(db *sql.DB, pager model.PageInfo) ([]model.AircraftData, int, error) {
+func (repo AircraftSqlRepo) GetAircraftItems(ctx context.Context, db *sql.DB, pager model.PageInfo, options model.ListOptions) ([]model.AircraftData, int, model.PageResult, error) {
 
-    query := util.AddOrderByClause(queryAircrafts, []model.OrderInfo{{Field: "Code"}})
-	query, args := util.AddPaginationClause(query, pager)
+    page, err := newKeysetPage(pager, stableOrder(options.Order, "code"), AircraftListFields)
+    if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
 
-    aircrafts, err := executeRowsQuery(db, query, args, 
+    query, args, err := aircraftPageQuery(page, options)
+    if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
+
+    aircrafts, err := executeRowsQuery(ctx, db, query, args, 
         func(rows *sql.Rows) (Aircraft, error) {
             var item Aircraft
 			err := rows.Scan(
 			    &item.Code,
-			    &item.NameRu,
-			    &item.NameEn,
+			    &item.Names,
 			    &item.Range,
             )
 			return item, err
@@ -88,7 +157,7 @@ func (repo AircraftSqlRepo) GetAircraftItems(db *sql.DB, pager model.PageInfo) (
     )
 
     if err != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса Aircraft: %w", err)
+		return nil, 0, model.PageResult{}, fmt.Errorf("ошибка запроса Aircraft: %w", err)
 	}
 
     // Собрать коды в массив
@@ -97,12 +166,12 @@ func (repo AircraftSqlRepo) GetAircraftItems(db *sql.DB, pager model.PageInfo) (
 	})
 
     // Готовим запрос на места
-	query = util.AddInClause(querySeatTypes, codes, "aircraft_code", "WHERE")
-    query = util.AddGroupClause(query, []string{"aircraft_code", "fare_conditions"})
-	query = util.AddOrderByClause(query, []model.OrderInfo{{Field: "Code"}, {Field: "SeatType"}})
+	query, args, err = seatTypesQuery(codes)
+    if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
 
-	var arg0 []any
-    seatTypes, err := executeRowsQuery(db, query, arg0, 
+    seatTypes, err := executeRowsQuery(ctx, db, query, args, 
         func(rows *sql.Rows) (SeatType, error) {
             var item SeatType
 			err := rows.Scan(
@@ -115,144 +184,140 @@ func (repo AircraftSqlRepo) GetAircraftItems(db *sql.DB, pager model.PageInfo) (
     )
 
     if err != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса SeatType: %w", err)
+		return nil, 0, model.PageResult{}, fmt.Errorf("ошибка запроса SeatType: %w", err)
 	}
 
     // Соединяем результаты основного запроса самолетов и данных их мест
-    aircraftItems := mapAircraftData(aircrafts, seatTypes)
-
-    total, err := executeRowQuery(db, queryTotal, arg0, 
-        func(row *sql.Row) (Total, error) {
-            var item Total
-			err := row.Scan(
-			    &item.Total,
-            )
-			return item, err
-		},
-    )
+    aircraftItems, pageResult := keysetResult(page, mapAircraftData(aircrafts, seatTypes), aircraftListKey)
 
+    total, err := aircraftTotal(ctx, db, page, options)
     if err != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса Total: %w", err)
+		return nil, 0, model.PageResult{}, fmt.Errorf("ошибка запроса Total: %w", err)
 	}  
 
-	return aircraftItems, total.Total, nil
+	return aircraftItems, total, pageResult, nil
 }
 
 // GetAircraftItems возвращает самолеты с пагинацией с использованием асинхронного подхода
-func (repo AircraftSqlRepo) GetAircraftItemsAsync(db *sql.DB, pager model.PageInfo) ([]model.AircraftData, int, error) {
-
-	// Запрос на получение общего количества самолетов
-	var arg0 []any
-    totalChan := executeRowQueryAsync(db, queryTotal, arg0, 
-        func(row *sql.Row) (Total, error) {
-            var item Total
-			err := row.Scan(
-			    &item.Total,
-            )
-			return item, err
-		},
-    )
-
-	// Запрос страницы самолетов
-    query := util.AddOrderByClause(queryAircrafts, []model.OrderInfo{{Field: "Code"}})
-	query, args := util.AddPaginationClause(query, pager)
+func (repo AircraftSqlRepo) GetAircraftItemsAsync(ctx context.Context, db *sql.DB, pager model.PageInfo, options model.ListOptions) ([]model.AircraftData, int, model.PageResult, error) {
 
-    aircraftsChan := executeRowsQueryAsync(db, query, args, 
-        func(rows *sql.Rows) (Aircraft, error) {
-            var item Aircraft
-			err := rows.Scan(
-			    &item.Code,
-			    &item.NameRu,
-			    &item.NameEn,
-			    &item.Range,
-            )
-			return item, err
-		},
-    )
-
-	aircraftsRes := <- aircraftsChan
+	var total int
+	var aircrafts []Aircraft
+	var seatTypes []SeatType
 
-    if aircraftsRes.Error != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса Aircraft: %w", aircraftsRes.Error)
+	page, err := newKeysetPage(pager, stableOrder(options.Order, "code"), AircraftListFields)
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
 	}
 
-    // Собрать коды в массив
-	codes := util.Map(*aircraftsRes.Items, func(p Aircraft) string {
-		return p.Code
-	})
-
-    // Готовим запрос на места
-	query = util.AddInClause(querySeatTypes, codes, "aircraft_code", "WHERE")
-    query = util.AddGroupClause(query, []string{"aircraft_code", "fare_conditions"})
-	query = util.AddOrderByClause(query, []model.OrderInfo{{Field: "Code"}, {Field: "SeatType"}})
+	executor := newQueryExecutor(ctx, maxParallelQueries)
 
-    seatTypesChan := executeRowsQueryAsync(db, query, arg0, 
-        func(rows *sql.Rows) (SeatType, error) {
-            var item SeatType
-			err := rows.Scan(
-			    &item.Code,
-			    &item.SeatType,
-			    &item.SeatCount,               
-            )
-			return item, err
-		},
-    )
+	// Запрос на получение общего количества самолетов
+	executor.Go(func(ctx context.Context) error {
+		var err error
+		total, err = aircraftTotal(ctx, db, page, options)
+		if err != nil {
+			return fmt.Errorf("ошибка запроса Total: %w", err)
+		}
+		return nil
+	})
 
-	seatTypesRes := <- seatTypesChan
+	// Запрос страницы самолетов и затем данных их мест
+	executor.Go(func(ctx context.Context) error {
+		query, args, err := aircraftPageQuery(page, options)
+		if err != nil {
+			return err
+		}
+
+		aircrafts, err = executeRowsQuery(ctx, db, query, args, 
+			func(rows *sql.Rows) (Aircraft, error) {
+				var item Aircraft
+				err := rows.Scan(
+					&item.Code,
+					&item.Names,
+					&item.Range,
+				)
+				return item, err
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка запроса Aircraft: %w", err)
+		}
+
+		// Собрать коды в массив
+		codes := util.Map(aircrafts, func(p Aircraft) string {
+			return p.Code
+		})
+
+		// Готовим запрос на места
+		query, args, err = seatTypesQuery(codes)
+		if err != nil {
+			return err
+		}
+
+		seatTypes, err = executeRowsQuery(ctx, db, query, args, 
+			func(rows *sql.Rows) (SeatType, error) {
+				var item SeatType
+				err := rows.Scan(
+					&item.Code,
+					&item.SeatType,
+					&item.SeatCount,               
+				)
+				return item, err
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка запроса SeatType: %w", err)
+		}
+		return nil
+	})
 
-    if seatTypesRes.Error != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса SeatType: %w", seatTypesRes.Error)
+	if err := executor.Wait(); err != nil {
+		return nil, 0, model.PageResult{}, err
 	}
 
     // Соединяем результаты основного запроса самолетов и данных их мест
-    aircraftItems := mapAircraftData(*aircraftsRes.Items, *seatTypesRes.Items)
-
-    // Пулучаем данные запроса общего количества самолетов
-	totalRes := <- totalChan
-
-    if totalRes.Error != nil {
-		return nil, 0, fmt.Errorf("ошибка запроса Total: %w", totalRes.Error)
-	}  
+    aircraftItems, pageResult := keysetResult(page, mapAircraftData(aircrafts, seatTypes), aircraftListKey)
 
-	return aircraftItems, totalRes.Item.Total, nil
+	return aircraftItems, total, pageResult, nil
 }
 
 
 // GetAircraftItemByCode возвращает самолет по коду
-func (repo AircraftSqlRepo) GetAircraftItemByCode(db *sql.DB, code string) (*model.AircraftData, error) {
+func (repo AircraftSqlRepo) GetAircraftItemByCode(ctx context.Context, db DBTX, code string) (*model.AircraftData, error) {
 
-	query := util.AddWhereClause(queryAircrafts, []string{"aircraft_code"}, 1, "WHERE", "AND")
+	query, args, err := aircraftByCodeQuery(code)
+    if err != nil {
+		return nil, err
+	}
 
-	args := []any{code}
-    aircraft, err := executeRowQuery(db, query, args, 
+    aircraft, err := executeRowQuery(ctx, db, query, args, 
         func(row *sql.Row) (Aircraft, error) {
 			var item Aircraft
 			err := row.Scan(
 			    &item.Code,
-			    &item.NameRu,
-			    &item.NameEn,
+			    &item.Names,
 			    &item.Range,
             )
 			return item, err
 		},
     )
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
     if err != nil {
 		return nil, fmt.Errorf("ошибка запроса Aircraft: %w", err)
 	}  	
 
-	if aircraft == nil {
-		return nil, nil
-	}
-
 
     // Готовим запрос на места
-	query = util.AddInClause(querySeatTypes, []string{aircraft.Code}, "aircraft_code", "WHERE")
-    query = util.AddGroupClause(query, []string{"aircraft_code", "fare_conditions"})
-	query = util.AddOrderByClause(query, []model.OrderInfo{{Field: "Code"}, {Field: "SeatType"}})
+	query, args, err = seatTypesQuery([]string{aircraft.Code})
+    if err != nil {
+		return nil, err
+	}
 
-	var arg0 []any
-    seatTypes, err := executeRowsQuery(db, query, arg0, 
+    seatTypes, err := executeRowsQuery(ctx, db, query, args, 
         func(rows *sql.Rows) (SeatType, error) {
             var item SeatType
 			err := rows.Scan(
@@ -274,60 +339,76 @@ func (repo AircraftSqlRepo) GetAircraftItemByCode(db *sql.DB, code string) (*mod
 	return &aircraftItem, nil
 }
 
-// GetAircraftItemByCode возвращает самолет по коду
-func (repo AircraftSqlRepo) GetAircraftItemByCodeAsync(db *sql.DB, code string) (*model.AircraftData, error) {
-
-	query := util.AddWhereClause(queryAircrafts, []string{"aircraft_code"}, 1, "WHERE", "AND")
-
-	args := []any{code}
-    aircraftChan := executeRowQueryAsync(db, query, args, 
-        func(row *sql.Row) (Aircraft, error) {
-			var item Aircraft
-			err := row.Scan(
-			    &item.Code,
-			    &item.NameRu,
-			    &item.NameEn,
-			    &item.Range,
-            )
-			return item, err
-		},
-    )
-
-    // Готовим запрос на места
-	query = util.AddInClause(querySeatTypes, []string{code}, "aircraft_code", "WHERE")
-    query = util.AddGroupClause(query, []string{"aircraft_code", "fare_conditions"})
-	query = util.AddOrderByClause(query, []model.OrderInfo{{Field: "Code"}, {Field: "SeatType"}})
-
-	var arg0 []any
-    seatTypesChan := executeRowsQueryAsync(db, query, arg0, 
-        func(rows *sql.Rows) (SeatType, error) {
-            var item SeatType
-			err := rows.Scan(
-			    &item.Code,
-			    &item.SeatType,
-			    &item.SeatCount,               
-            )
-			return item, err
-		},
-    )
+// GetAircraftItemByCode возвращает самолет по коду, запросы самолета и его мест выполняются параллельно.
+// Если самолет не найден, возвращается nil без ошибки.
+func (repo AircraftSqlRepo) GetAircraftItemByCodeAsync(ctx context.Context, db *sql.DB, code string) (*model.AircraftData, error) {
+
+	var aircraft *Aircraft
+	var seatTypes []SeatType
+
+	executor := newQueryExecutor(ctx, maxParallelQueries)
+
+	executor.Go(func(ctx context.Context) error {
+		query, args, err := aircraftByCodeQuery(code)
+		if err != nil {
+			return err
+		}
+
+		aircraft, err = executeRowQuery(ctx, db, query, args, 
+			func(row *sql.Row) (Aircraft, error) {
+				var item Aircraft
+				err := row.Scan(
+					&item.Code,
+					&item.Names,
+					&item.Range,
+				)
+				return item, err
+			},
+		)
+		if errors.Is(err, sql.ErrNoRows) {
+			aircraft = nil
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("ошибка запроса Aircraft: %w", err)
+		}
+		return nil
+	})
 
-	aircraftRes := <- aircraftChan
-	seatTypesRes := <- seatTypesChan;
-	
-    if aircraftRes.Error != nil {
-		return nil, fmt.Errorf("ошибка запроса Aircraft: %w", aircraftRes.Error)
-	}  	
+	executor.Go(func(ctx context.Context) error {
+		// Готовим запрос на места
+		query, args, err := seatTypesQuery([]string{code})
+		if err != nil {
+			return err
+		}
+
+		seatTypes, err = executeRowsQuery(ctx, db, query, args, 
+			func(rows *sql.Rows) (SeatType, error) {
+				var item SeatType
+				err := rows.Scan(
+					&item.Code,
+					&item.SeatType,
+					&item.SeatCount,               
+				)
+				return item, err
+			},
+		)
+		if err != nil {
+			return fmt.Errorf("ошибка запроса SeatType: %w", err)
+		}
+		return nil
+	})
 
-	if aircraftRes.Item == nil {
-		return nil, nil
+	if err := executor.Wait(); err != nil {
+		return nil, err
 	}
 
-    if seatTypesRes.Error != nil {
-		return nil, fmt.Errorf("ошибка запроса SeatType: %w", seatTypesRes.Error)
+	if aircraft == nil {
+		return nil, nil
 	}
 
 	// Соединяем результаты основного запроса самолетов и данных их мест
-	aircraftItem := mapAircraftItem(*aircraftRes.Item, *seatTypesRes.Items);
+	aircraftItem := mapAircraftItem(*aircraft, seatTypes);
 
 	return &aircraftItem, nil
 }
@@ -335,12 +416,12 @@ func (repo AircraftSqlRepo) GetAircraftItemByCodeAsync(db *sql.DB, code string)
 
 
 // GetAircraftItems возвращает самолеты с пагинацией
-func (repo AircraftSqlRepo) GetExistsByCode(db *sql.DB, code string) (bool, error) {
+func (repo AircraftSqlRepo) GetExistsByCode(ctx context.Context, db DBTX, code string) (bool, error) {
 
 	query := isExistsAircraft
 
 	args := []any{code}
-    exists, err := executeRowQuery(db, query, args, 
+    exists, err := executeRowQuery(ctx, db, query, args, 
         func(row *sql.Row) (bool, error) {
 			var exists bool
 			err := row.Scan(
@@ -358,64 +439,220 @@ func (repo AircraftSqlRepo) GetExistsByCode(db *sql.DB, code string) (bool, erro
 }
 
 
-func (repo AircraftSqlRepo) CreateAircraft(db *sql.DB, input model.AircraftInput) (*model.AircraftData, error) {
+func (repo AircraftSqlRepo) CreateAircraft(ctx context.Context, db DBTX, input model.AircraftInput) (*model.AircraftData, error) {
 
 	query := createAircraft
 
-	stmt, err := db.Prepare(query)
+	stmt, err := db.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подготовки запроса CreateAircraft: %w", err)
 	}
 	defer stmt.Close()
 
-	nameLabel := model.NameInput{En: input.NameEn, Ru: input.NameRu }
-    jmodel, err := json.Marshal(nameLabel)
-    if err != nil {
-       return nil, fmt.Errorf("ошибка подготовки json парамента для CreateAircraft: %w", err)
-    }
+	names := inputNames(input.Names, input.NameRu, input.NameEn)
 
-	if _, err := stmt.Exec(input.Code, string(jmodel), input.Range); err != nil {
+	if _, err := stmt.ExecContext(ctx, input.Code, names, input.Range); err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса CreateAircraft: %w", err)
 	}
 
-	return repo.GetAircraftItemByCode(db, input.Code)
+	return repo.GetAircraftItemByCode(ctx, db, input.Code)
 
 }
 
-func (repo AircraftSqlRepo) UpdateAircraft(db *sql.DB, input model.AircraftInput) (*model.AircraftData, error) {
+func (repo AircraftSqlRepo) UpdateAircraft(ctx context.Context, db DBTX, input model.AircraftInput) (*model.AircraftData, error) {
 
 	query := updateAircraft
 
-	stmt, err := db.Prepare(query)
+	stmt, err := db.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подготовки запроса UpdateAircraft: %w", err)
 	}
 	defer stmt.Close()
 
-	if _, err := stmt.Exec(input.Code, input.NameEn, input.NameRu, input.Range); err != nil {
+	names := inputNames(input.Names, input.NameRu, input.NameEn)
+
+	if _, err := stmt.ExecContext(ctx, input.Code, names, input.Range); err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса UpdateAircraft: %w", err)
 	}
 
-	return repo.GetAircraftItemByCode(db, input.Code)
+	return repo.GetAircraftItemByCode(ctx, db, input.Code)
 }
 
-func (repo AircraftSqlRepo) DeleteAircraft(db *sql.DB, code string) (*string, error) {
+// ReplaceAircraft записывает самолет целиком: в отличие от UpdateAircraft названия заменяются,
+// а не дополняются, поэтому языки, которых нет во входных данных, удаляются
+func (repo AircraftSqlRepo) ReplaceAircraft(ctx context.Context, db DBTX, input model.AircraftInput) (*model.AircraftData, error) {
+
+	stmt, err := db.PrepareContext(ctx, replaceAircraft)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса ReplaceAircraft: %w", err)
+	}
+	defer stmt.Close()
+
+	names := inputNames(input.Names, input.NameRu, input.NameEn)
+
+	if _, err := stmt.ExecContext(ctx, input.Code, names, input.Range); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса ReplaceAircraft: %w", err)
+	}
+
+	return repo.GetAircraftItemByCode(ctx, db, input.Code)
+}
+
+func (repo AircraftSqlRepo) DeleteAircraft(ctx context.Context, db DBTX, code string) (*string, error) {
 
 	query := deleteAircraft
 
-	stmt, err := db.Prepare(query)
+	stmt, err := db.PrepareContext(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("ошибка подготовки запроса DeleteAircraft: %w", err)
 	}
 	defer stmt.Close()
 
-	if _, err := stmt.Exec(code); err != nil {
+	if _, err := stmt.ExecContext(ctx, code); err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса DeleteAircraft: %w", err)
 	}
 
 	return &code, nil
 }
 
+// GetAircraftForUpdate читает код, названия и дальность самолета и блокирует строку до конца транзакции,
+// чтобы версия не изменилась между проверкой и записью. Места не читаются.
+func (repo AircraftSqlRepo) GetAircraftForUpdate(ctx context.Context, db DBTX, code string) (*model.AircraftData, error) {
+
+	query, args, err := aircraftByCodeQuery(code)
+    if err != nil {
+		return nil, err
+	}
+
+    aircraft, err := executeRowQuery(ctx, db, query+" for update", args, 
+        func(row *sql.Row) (Aircraft, error) {
+			var item Aircraft
+			err := row.Scan(
+			    &item.Code,
+			    &item.Names,
+			    &item.Range,
+            )
+			return item, err
+		},
+    )
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+    if err != nil {
+		return nil, fmt.Errorf("ошибка запроса блокировки Aircraft: %w", err)
+	}
+
+	aircraftItem := mapAircraftItem(*aircraft, nil)
+
+	return &aircraftItem, nil
+}
+
+// GetAircraftDependencies возвращает количество записей, ссылающихся на самолет
+func (repo AircraftSqlRepo) GetAircraftDependencies(ctx context.Context, db DBTX, code string) (*model.AircraftDependencies, error) {
+
+	args := []any{code}
+	dependencies, err := executeRowQuery(ctx, db, queryAircraftDependencies, args,
+		func(row *sql.Row) (model.AircraftDependencies, error) {
+			item := model.AircraftDependencies{Code: code}
+			err := row.Scan(
+				&item.Seats,
+				&item.FutureFlights,
+				&item.PastFlights,
+			)
+			return item, err
+		},
+	)
+
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса зависимостей Aircraft: %w", err)
+	}
+
+	return dependencies, nil
+}
+
+// GetAircraftNames возвращает коды и названия всех самолетов для поиска без данных о местах
+func (repo AircraftSqlRepo) GetAircraftNames(ctx context.Context, db *sql.DB) ([]model.AircraftData, error) {
+
+	aircrafts, err := executeRowsQuery(ctx, db, queryAircrafts, nil,
+		func(rows *sql.Rows) (Aircraft, error) {
+			var item Aircraft
+			err := rows.Scan(
+				&item.Code,
+				&item.Names,
+				&item.Range,
+			)
+			return item, err
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка запроса AircraftNames: %w", err)
+	}
+
+	return mapAircraftData(aircrafts, nil), nil
+}
+
+// aircraftPageQuery строит запрос страницы самолетов с фильтром, сортировкой и границей курсора
+func aircraftPageQuery(page keysetPage, options model.ListOptions) (string, []any, error) {
+	return util.NewQueryBuilder(queryAircrafts).
+		Filter(options.Filters, AircraftListFields).
+		Codes("aircraft_code", options.Codes).
+		Keyset(page.cursor, page.query, AircraftListFields).
+		OrderBy(page.query, AircraftListFields).
+		Page(page.pager).
+		Build()
+}
+
+// aircraftListKey возвращает значение поля сортировки самолета для курсора страницы
+func aircraftListKey(item model.AircraftData, field string) any {
+	switch field {
+	case "nameRu":
+		return item.NameRu
+	case "nameEn":
+		return item.NameEn
+	case "range":
+		return item.Range
+	default:
+		return item.Code
+	}
+}
+
+// aircraftTotal возвращает количество самолетов по фильтру способом подсчета страницы
+func aircraftTotal(ctx context.Context, db *sql.DB, page keysetPage, options model.ListOptions) (int, error) {
+	countQuery, args, err := util.NewQueryBuilder(queryTotal).
+		Filter(options.Filters, AircraftListFields).
+		Codes("aircraft_code", options.Codes).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	selectQuery, _, err := util.NewQueryBuilder(queryAircrafts).
+		Filter(options.Filters, AircraftListFields).
+		Codes("aircraft_code", options.Codes).
+		Build()
+	if err != nil {
+		return 0, err
+	}
+
+	return countRows(ctx, db, page.total, "bookings.aircrafts_data", len(options.Filters) > 0 || options.Codes != nil,
+		countQuery, selectQuery, args)
+}
+
+// aircraftByCodeQuery строит запрос самолета по коду
+func aircraftByCodeQuery(code string) (string, []any, error) {
+	return util.NewQueryBuilder(queryAircrafts).
+		WhereEq("aircraft_code", code).
+		Build()
+}
+
+// seatTypesQuery строит запрос количества мест по классам для списка самолетов
+func seatTypesQuery(codes []string) (string, []any, error) {
+	return util.NewQueryBuilder(querySeatTypes).
+		WhereIn("aircraft_code", util.Args(codes)).
+		GroupBy("aircraft_code", "fare_conditions").
+		OrderBy([]model.OrderInfo{{Field: "code"}, {Field: "seatType"}}, seatTypeOrderColumns).
+		Build()
+}
 
 func mapAircraftItem(aircraft Aircraft, seatTypes []SeatType) (model.AircraftData) {
 	items := mapAircraftData([]Aircraft{aircraft}, seatTypes)
@@ -429,7 +666,8 @@ func mapAircraftData(aircrafts []Aircraft, seatTypes []SeatType) ([]model.Aircra
 	})	
     
     aircraftItems := util.Map(aircrafts, func(p Aircraft) model.AircraftData {
-		item :=  model.AircraftData{ Code: p.Code, NameRu: p.NameRu, NameEn: p.NameEn, Range: p.Range } 
+		item :=  model.AircraftData{ Code: p.Code, NameRu: p.Names.Get("ru"), NameEn: p.Names.Get("en"),
+			Names: p.Names.Languages(), Range: p.Range } 
         seats, exists := seatMap[item.Code]
         if exists {
             seatItems := util.Map(seats, func(p SeatType) model.SeatData {
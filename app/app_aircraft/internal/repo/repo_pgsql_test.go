@@ -24,13 +24,13 @@ func TestDBConnection(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
     
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
    
 
     // Проверяем, что подключение действительно работает
@@ -60,13 +60,13 @@ func TestDBConnectionWithTimeout(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
      
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     // Устанавливаем таймаут для Ping
     db.SetConnMaxLifetime(time.Minute * 3)
@@ -110,17 +110,17 @@ func TestGetAircraftItems(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
    
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     pager := model.PageInfo{Limit: util.Ptr(5), Offset: util.Ptr(5)}
 
-    aircraftItems, total, err := repo.GetAircraftItems(db, pager)
+    aircraftItems, total, _, err := repo.GetAircraftItems(context.Background(), db, pager, model.ListOptions{})
     if err != nil {
 		t.Errorf("Ошибка запроса данных 'GetAircrafts': %v", err)
     }
@@ -140,17 +140,17 @@ func TestGetAircraftItemsAsync(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
    
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     pager := model.PageInfo{Limit: util.Ptr(5), Offset: util.Ptr(5)}
 
-    aircraftItems, total, err := repo.GetAircraftItemsAsync(db, pager)
+    aircraftItems, total, _, err := repo.GetAircraftItemsAsync(context.Background(), db, pager, model.ListOptions{})
     if err != nil {
 		t.Errorf("Ошибка запроса данных 'GetAircrafts': %v", err)
     }
@@ -172,19 +172,19 @@ func TestGetAircraftItemByCode(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
 	//codeOk := "SU9"
     codeBad := "AN1"
 
 
-    _, err = repo.GetAircraftItemByCode(db, codeBad)
+    _, err = repo.GetAircraftItemByCode(context.Background(), db, codeBad)
     if err == nil {
 		t.Errorf("Ошибка поиска самолета 'GetAircraft': %v", err)
     } 
@@ -201,17 +201,17 @@ func TestGetAircraftItemByCodeAsync(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     codeOk := "100"
 
-    _, err = repo.GetAircraftItemByCodeAsync(db, codeOk)
+    _, err = repo.GetAircraftItemByCodeAsync(context.Background(), db, codeOk)
     if err != nil {
 		t.Errorf("Ошибка поиска самолета 'GetAircraft': %v", err)
     } 
@@ -228,17 +228,17 @@ func TestGetExistsByCodeSuccess(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     code := "100"
 
-    exists, err := repo.GetExistsByCode(db, code)
+    exists, err := repo.GetExistsByCode(context.Background(), db, code)
     if err != nil {
 		t.Errorf("Ошибка запроса проверки существования самолета 'GetExistsByCode': %v", err)
     } 
@@ -256,17 +256,17 @@ func TestGetExistsByCodeFail(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     code := "AN1"
 
-    exists, err := repo.GetExistsByCode(db, code)
+    exists, err := repo.GetExistsByCode(context.Background(), db, code)
     if err != nil {
 		t.Errorf("Ошибка запроса проверки существования самолета 'GetExistsByCode': %v", err)
     } 
@@ -283,17 +283,17 @@ func TestUpdateAircraft(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     input := model.AircraftInput{ Code: "TUS", NameRu: "ТУ 134!", NameEn: "TU 1341", Range: 3531}
 
-    aircraft, err := repo.UpdateAircraft(db, input)
+    aircraft, err := repo.UpdateAircraft(context.Background(), db, input)
     if err != nil {
 		t.Errorf("Ошибка обновления самолета 'UpdateAircraft': %v", err)
     } 
@@ -310,17 +310,17 @@ func TestDeleteAircraft(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     input := "TUS";
     
-    code, err := repo.DeleteAircraft(db, input)
+    code, err := repo.DeleteAircraft(context.Background(), db, input)
     if err != nil {
 		t.Errorf("Ошибка удаления самолета 'DeleteAircraft': %v", err)
     } 
@@ -337,17 +337,17 @@ func TestCreateAircraft(t *testing.T) {
     }
 
     // создать экземпляр репозитория
-    repo := AircraftSqlRepo{Configuration: config};
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
       
     db, err := repo.GetDBConnection()
     if err != nil {
         t.Fatalf("Не удалось подключиться к базе данных: %v", err)
     }
-    defer db.Close()
+    defer repo.Close()
 
     input := model.AircraftInput{ Code: "TUS", NameRu: "ТУ 134", NameEn: "TU 134", Range: 2500}
 
-    aircraft, err := repo.CreateAircraft(db, input)
+    aircraft, err := repo.CreateAircraft(context.Background(), db, input)
     if err != nil {
 		t.Errorf("Ошибка создания самолета 'CreateAircraft': %v", err)
     } 
@@ -0,0 +1,169 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+var (
+	querySeats = `select
+		aircraft_code as "Code"
+		, seat_no as "SeatNo"
+		, fare_conditions as "SeatType"
+		from bookings.seats
+		where aircraft_code = $1
+		order by (regexp_replace(seat_no, '\D', '', 'g'))::int, seat_no`
+
+	createSeat = `insert into bookings.seats ("aircraft_code", "seat_no", "fare_conditions") values ($1, $2, $3)`
+	updateSeat = `update bookings.seats set "fare_conditions" = $3 where "aircraft_code" = $1 and "seat_no" = $2`
+	deleteSeat = `delete from bookings.seats where "aircraft_code" = $1 and "seat_no" = $2`
+	deleteAircraftSeats = `delete from bookings.seats where "aircraft_code" = $1`
+
+	isExistsSeat = `SELECT EXISTS (SELECT 1 FROM bookings.seats WHERE "aircraft_code" = $1 and "seat_no" = $2);`
+)
+
+// GetSeatItems возвращает схему мест самолета
+func (repo AircraftSqlRepo) GetSeatItems(ctx context.Context, db DBTX, code string) (*model.AircraftSeatsData, error) {
+
+	args := []any{code}
+    seats, err := executeRowsQuery(ctx, db, querySeats, args,
+        func(rows *sql.Rows) (Seat, error) {
+            var item Seat
+			err := rows.Scan(
+			    &item.Code,
+			    &item.SeatNo,
+			    &item.SeatType,
+            )
+			return item, err
+		},
+    )
+
+    if err != nil {
+		return nil, fmt.Errorf("ошибка запроса Seat: %w", err)
+	}
+
+	seatsItem := mapAircraftSeatsData(code, seats)
+
+	return &seatsItem, nil
+}
+
+// GetSeatExists проверяет наличие места в самолете
+func (repo AircraftSqlRepo) GetSeatExists(ctx context.Context, db DBTX, code string, seatNo string) (bool, error) {
+
+	args := []any{code, seatNo}
+    exists, err := executeRowQuery(ctx, db, isExistsSeat, args,
+        func(row *sql.Row) (bool, error) {
+			var exists bool
+			err := row.Scan(
+				&exists,
+            )
+			return exists, err
+		},
+    )
+
+    if err != nil {
+		return false, fmt.Errorf("ошибка запроса проверки Seat: %w", err)
+	}
+
+	return *exists, nil
+}
+
+func (repo AircraftSqlRepo) CreateSeat(ctx context.Context, db DBTX, input model.SeatInput) (*model.AircraftSeatsData, error) {
+
+	stmt, err := db.PrepareContext(ctx, createSeat)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса CreateSeat: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, input.Code, input.SeatNumb, input.SeatType); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса CreateSeat: %w", err)
+	}
+
+	return repo.GetSeatItems(ctx, db, input.Code)
+}
+
+func (repo AircraftSqlRepo) UpdateSeat(ctx context.Context, db DBTX, input model.SeatInput) (*model.AircraftSeatsData, error) {
+
+	stmt, err := db.PrepareContext(ctx, updateSeat)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса UpdateSeat: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, input.Code, input.SeatNumb, input.SeatType); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса UpdateSeat: %w", err)
+	}
+
+	return repo.GetSeatItems(ctx, db, input.Code)
+}
+
+func (repo AircraftSqlRepo) DeleteSeat(ctx context.Context, db DBTX, code string, seatNo string) (*model.AircraftSeatsData, error) {
+
+	stmt, err := db.PrepareContext(ctx, deleteSeat)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса DeleteSeat: %w", err)
+	}
+	defer stmt.Close()
+
+	if _, err := stmt.ExecContext(ctx, code, seatNo); err != nil {
+		return nil, fmt.Errorf("ошибка выполнения запроса DeleteSeat: %w", err)
+	}
+
+	return repo.GetSeatItems(ctx, db, code)
+}
+
+// DeleteAircraftSeats удаляет все места самолета и возвращает количество удаленных мест
+func (repo AircraftSqlRepo) DeleteAircraftSeats(ctx context.Context, db DBTX, code string) (int64, error) {
+
+	result, err := db.ExecContext(ctx, deleteAircraftSeats, code)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка выполнения запроса DeleteAircraftSeats: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// ReplaceSeatLayout заменяет все места самолета новой схемой.
+// Удаление и добавление мест атомарны только внутри единицы работы, поэтому db - транзакция.
+func (repo AircraftSqlRepo) ReplaceSeatLayout(ctx context.Context, db DBTX, code string, seats []model.SeatItemData) (*model.AircraftSeatsData, error) {
+
+	if _, err := repo.DeleteAircraftSeats(ctx, db, code); err != nil {
+		return nil, fmt.Errorf("ошибка удаления мест ReplaceSeatLayout: %w", err)
+	}
+
+	stmt, err := db.PrepareContext(ctx, createSeat)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка подготовки запроса ReplaceSeatLayout: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, seat := range seats {
+		if _, err := stmt.ExecContext(ctx, code, seat.SeatNo, seat.SeatType); err != nil {
+			return nil, fmt.Errorf("ошибка добавления места '%s' ReplaceSeatLayout: %w", seat.SeatNo, err)
+		}
+	}
+
+	return repo.GetSeatItems(ctx, db, code)
+}
+
+// mapAircraftSeatsData собирает схему мест и пересчитывает количество мест по классам
+func mapAircraftSeatsData(code string, seats []Seat) (model.AircraftSeatsData) {
+
+	seatItems := util.Map(seats, func(p Seat) model.SeatItemData {
+		return model.SeatItemData{SeatNo: p.SeatNo, SeatType: p.SeatType}
+	})
+
+	seatTypes := util.CountSeatTypes(seatItems)
+
+	return model.AircraftSeatsData{
+		Code: code,
+		SeatCount: len(seatItems),
+		Seats: &seatTypes,
+		Items: &seatItems,
+	}
+}
@@ -0,0 +1,66 @@
+package repo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/conf"
+)
+
+// TestGetSeatItems тестирует получение схемы мест самолета
+func TestGetSeatItems(t *testing.T) {
+
+    // создать экземпляр конфигурации и загрузить данные
+    config, err := conf.Configuration{}.New().LoadConfiguration("./../.."); 
+
+    if err != nil {
+        t.Errorf("Не удалось загрузить конфигурацию: %v", err)
+    }
+
+    // создать экземпляр репозитория
+    repo, _ := AircraftSqlRepo{}.NewAircraftSqlRepo(config);
+
+    db, err := repo.GetDBConnection()
+    if err != nil {
+        t.Fatalf("Не удалось подключиться к базе данных: %v", err)
+    }
+    defer repo.Close()
+
+    seats, err := repo.GetSeatItems(context.Background(), db, "SU9")
+    if err != nil {
+		t.Fatalf("Ошибка запроса данных 'GetSeatItems': %v", err)
+    }
+
+	if seats.SeatCount != len(*seats.Items) {
+		t.Errorf("Количество мест %v не совпадает с числом элементов %v", seats.SeatCount, len(*seats.Items))
+	}
+
+	t.Logf("Получено %v мест", seats.SeatCount)
+}
+
+// TestMapAircraftSeatsData тестирует пересчет количества мест по классам
+func TestMapAircraftSeatsData(t *testing.T) {
+
+	seats := []Seat{
+		{Code: "SU9", SeatNo: "1A", SeatType: "Business"},
+		{Code: "SU9", SeatNo: "1C", SeatType: "Business"},
+		{Code: "SU9", SeatNo: "4A", SeatType: "Economy"},
+	}
+
+	data := mapAircraftSeatsData("SU9", seats)
+
+	if data.SeatCount != 3 {
+		t.Errorf("Ожидалось 3 места, получено %v", data.SeatCount)
+	}
+
+	expected := map[string]int{"Business": 2, "Economy": 1}
+	if len(*data.Seats) != len(expected) {
+		t.Fatalf("Ожидалось %v классов, получено %v", len(expected), len(*data.Seats))
+	}
+
+	for _, st := range *data.Seats {
+		if expected[st.SeatType] != st.Count {
+			t.Errorf("Класс '%v': ожидалось %v мест, получено %v", st.SeatType, expected[st.SeatType], st.Count)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DBTX - общий интерфейс пула *sql.DB и транзакции *sql.Tx.
+// Методы репозитория, принимающие DBTX, выполняются как в пуле, так и внутри единицы работы.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Определяем интерфейс единицы работы IUnitOfWork: действия fn выполняются в одной транзакции,
+// которая фиксируется при успешном завершении и откатывается при ошибке или панике.
+// T - то, через что fn обращается к базе внутри транзакции: DBTX или репозиторий, связанный с транзакцией.
+type IUnitOfWork[T any] interface {
+	Do(ctx context.Context, fn func(tx T) error) error
+}
+
+// Единица работы над пулом database/sql
+type SqlUnitOfWork struct {
+	DB *sql.DB
+}
+
+func (uow SqlUnitOfWork) Do(ctx context.Context, fn func(tx DBTX) error) (err error) {
+
+	tx, err := uow.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("ошибка начала транзакции: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("ошибка фиксации транзакции: %w", err)
+	}
+
+	return nil
+}
+
+// Единица работы над gorm. Bind связывает транзакцию gorm с репозиторием,
+// чтобы fn вызывал обычные методы репозитория внутри транзакции.
+type GormUnitOfWork[T any] struct {
+	DB   *gorm.DB
+	Bind func(tx *gorm.DB) T
+}
+
+func (uow GormUnitOfWork[T]) Do(ctx context.Context, fn func(tx T) error) error {
+	return uow.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(uow.Bind(tx))
+	})
+}
@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// TestSqlUnitOfWork проверяет фиксацию транзакции при успехе и откат при ошибке или панике
+func TestSqlUnitOfWork(t *testing.T) {
+	errWrite := errors.New("write failed")
+
+	tests := []struct {
+		name      string
+		fn        func(tx DBTX) error
+		wantErr   error
+		wantPanic bool
+		commits   int32
+		rollbacks int32
+	}{
+		{"Commit", func(tx DBTX) error {
+			var n int
+			return tx.QueryRowContext(context.Background(), "select 1").Scan(&n)
+		}, nil, false, 1, 0},
+		{"Rollback_Error", func(tx DBTX) error { return errWrite }, errWrite, false, 0, 1},
+		{"Rollback_QueryError", func(tx DBTX) error {
+			_, err := tx.QueryContext(context.Background(), "insert into bookings.seats")
+			return err
+		}, nil, false, 0, 1},
+		{"Rollback_Panic", func(tx DBTX) error { panic("write panic") }, nil, true, 0, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, connector := newFakeDB(t, fakeQuery{Match: "select 1",
+				Columns: []string{"n"}, Rows: [][]driver.Value{{int64(1)}}})
+
+			var err error
+			panicked := func() (panicked bool) {
+				defer func() { panicked = recover() != nil }()
+				err = SqlUnitOfWork{DB: db}.Do(context.Background(), tt.fn)
+				return false
+			}()
+
+			if panicked != tt.wantPanic {
+				t.Fatalf("Паника: ожидалось %v, получено %v", tt.wantPanic, panicked)
+			}
+
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Ожидалась ошибка %v, получено %v", tt.wantErr, err)
+			}
+
+			if tt.commits == 1 && err != nil {
+				t.Errorf("Неожиданная ошибка: %v", err)
+			}
+
+			if commits, rollbacks := connector.commits.Load(), connector.rollbacks.Load(); commits != tt.commits || rollbacks != tt.rollbacks {
+				t.Errorf("Ожидалось фиксаций %d и откатов %d, получено %d и %d", tt.commits, tt.rollbacks, commits, rollbacks)
+			}
+		})
+	}
+}
+
+// TestGormUnitOfWorkSequentialReads проверяет, что чтение аэропорта в единице работы
+// выполняет запросы по одному в соединении транзакции, а не параллельно.
+// Одновременные запросы считаются по обработчикам gorm: database/sql сам упорядочивает вызовы драйвера
+// в одном соединении, а pgx отклоняет запрос, пока не прочитан результат предыдущего.
+func TestGormUnitOfWorkSequentialReads(t *testing.T) {
+	db, connector := newFakeDB(t,
+		fakeQuery{Match: "airports_data", Delay: 20 * time.Millisecond,
+			Columns: []string{"airport_code", "airport_name", "city", "coordinates", "timezone"},
+			Rows: [][]driver.Value{{"SVO", []byte(`{"ru": "Шереметьево", "en": "Sheremetyevo"}`),
+				[]byte(`{"ru": "Москва", "en": "Moscow"}`), "(37.4146,55.972599)", "Europe/Moscow"}}},
+		fakeQuery{Match: "from bookings.flights", Delay: 20 * time.Millisecond,
+			Columns: []string{"flight_id", "flight_no", "departure_airport", "arrival_airport", "source"},
+			Rows:    [][]driver.Value{{int64(1), "PG0402", "SVO", "LED", "departure"}}})
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{
+		NamingStrategy:       schema.NamingStrategy{TablePrefix: airschema + "."},
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("Ошибка открытия gorm: %v", err)
+	}
+
+	var active, maxActive atomic.Int32
+	gdb.Callback().Query().Before("gorm:query").Register("test:begin", func(*gorm.DB) {
+		n := active.Add(1)
+		for {
+			max := maxActive.Load()
+			if n <= max || maxActive.CompareAndSwap(max, n) {
+				break
+			}
+		}
+	})
+	gdb.Callback().Query().After("gorm:after_query").Register("test:end", func(*gorm.DB) {
+		active.Add(-1)
+	})
+
+	uow, err := GormDBContext{GormDb: gdb}.UnitOfWork()
+	if err != nil {
+		t.Fatalf("Ошибка получения единицы работы: %v", err)
+	}
+
+	err = uow.Do(context.Background(), func(tx IAirportRepo) error {
+		airport, err := tx.GetAitportItemByCode(context.Background(), "SVO")
+		if err != nil {
+			return err
+		}
+		if airport == nil || airport.Code != "SVO" {
+			t.Errorf("Неверный аэропорт: %+v", airport)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if max := maxActive.Load(); max != 1 {
+		t.Errorf("В транзакции одновременно выполнялось %d запросов, ожидалось по одному", max)
+	}
+
+	if conns, commits := connector.conns.Load(), connector.commits.Load(); conns != 1 || commits != 1 {
+		t.Errorf("Ожидалось одно соединение и одна фиксация, получено соединений %d и фиксаций %d", conns, commits)
+	}
+}
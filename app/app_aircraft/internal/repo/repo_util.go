@@ -1,17 +1,46 @@
 package repo
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"slices"
 
+	"github.com/snpavlov/app_aircraft/internal/conf"
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
 	"github.com/snpavlov/app_aircraft/internal/model"
-	"gorm.io/gorm"
+	"github.com/snpavlov/app_aircraft/internal/util"
 )
 
-func executeRowsQuery[T any](db *sql.DB, query string, args []interface{}, 
+// Оценка числа строк таблицы по статистике планировщика
+const queryTableEstimate = `select reltuples from pg_class where oid = to_regclass($1)`
+
+// applyPoolSettings настраивает пул соединений; нулевые значения оставляют настройки по умолчанию
+func applyPoolSettings(db *sql.DB, settings conf.PoolSettings) {
+
+	if settings.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(settings.MaxOpenConns) // Максимум открытых соединений
+	}
+
+	if settings.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(settings.MaxIdleConns) // Максимум соединений в режиме ожидания
+	}
+
+	if settings.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(settings.ConnMaxLifetime) // Время жизни соединения
+	}
+
+	if settings.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(settings.ConnMaxIdleTime) // Время простоя соединения
+	}
+}
+
+func executeRowsQuery[T any](ctx context.Context, db DBTX, query string, args []interface{}, 
     scanFn func(*sql.Rows) (T, error)) ([]T, error) {
 	
-    rows, err := db.Query(query, args...)
+    rows, err := db.QueryContext(ctx, query, args...)
     if err != nil {
 		return nil, fmt.Errorf("ошибка выполнения запроса: %w", err)
 	}
@@ -33,11 +62,11 @@ func executeRowsQuery[T any](db *sql.DB, query string, args []interface{},
     return items, nil
 }
 
-func executeRowQuery[T any](db *sql.DB, query string, args []interface{}, 
+func executeRowQuery[T any](ctx context.Context, db DBTX, query string, args []interface{}, 
     scanFn func(*sql.Row) (T, error)) (*T, error) {
 	
     var item T
-    row := db.QueryRow(query, args...)
+    row := db.QueryRowContext(ctx, query, args...)
 	item, err := scanFn(row)
     if err != nil {
         return nil, fmt.Errorf("ошибка сканирования строки: %w", err)
@@ -46,92 +75,168 @@ func executeRowQuery[T any](db *sql.DB, query string, args []interface{},
     return &item, nil
 }
 
+// stableOrder добавляет ключевое поле в конец сортировки, если клиент его не указал
+func stableOrder(order []model.OrderInfo, key string) []model.OrderInfo {
+	for _, item := range order {
+		if item.Field == key {
+			return order
+		}
+	}
+	return append(order[:len(order):len(order)], model.OrderInfo{Field: key})
+}
 
-func executeRowsQueryAsync[T any](db *sql.DB, query string, args []interface{}, 
-    scanFn func(*sql.Rows) (T, error)) <-chan model.ChannelListResult[T] {
-	
-	resultChan := make(chan model.ChannelListResult[T], 1)
+// keysetPage - чтение страницы по смещению или по курсору.
+// Запрашивается на одну строку больше размера страницы, чтобы узнать, есть ли следующая.
+type keysetPage struct {
+	order  []model.OrderInfo // сортировка ответа
+	query  []model.OrderInfo // сортировка запроса, развернута при чтении назад
+	cursor *util.Cursor
+	pager  model.PageInfo    // параметры LIMIT/OFFSET запроса
+	size   *int
+	offset *int
+	total  model.TotalMode
+}
 
-	go func() {
-        defer close(resultChan)
+// newKeysetPage разбирает курсор страницы; неверный курсор возвращается ошибкой валидации
+func newKeysetPage(pager model.PageInfo, order []model.OrderInfo, columns util.ListFields) (keysetPage, error) {
+	page := keysetPage{order: order, query: order, size: pager.Limit, offset: pager.Offset, total: pager.TotalMode}
+
+	switch page.total {
+	case "":
+		page.total = model.TotalExact
+	case model.TotalExact, model.TotalEstimated, model.TotalNone:
+	default:
+		return page, domain.NewValidationError(i18n.MsgPageInvalid, i18n.Validation("total", i18n.MsgPageTotal, page.total))
+	}
 
-		rows, err := db.Query(query, args...)
+	if pager.Cursor != nil && *pager.Cursor != "" {
+		cursor, err := util.DecodeCursor(*pager.Cursor, order, columns)
 		if err != nil {
-			resultChan <- model.ChannelListResult[T]{Error: fmt.Errorf("ошибка выполнения запроса: %w", err)}
-			return
+			return page, domain.NewValidationError(i18n.MsgPageInvalid, i18n.ValidationOf("cursor", err))
 		}
-		defer rows.Close()
-
-		var items []T
-		for rows.Next() {
-			item, err := scanFn(rows)
-			if err != nil {
-				resultChan <- model.ChannelListResult[T]{Error: fmt.Errorf("ошибка сканирования строки: %w", err)}
-			}
-			items = append(items, item)
+		page.cursor = &cursor
+		page.offset = nil
+		if cursor.Prev {
+			page.query = util.ReverseOrder(order)
 		}
-		resultChan <- model.ChannelListResult[T]{Items: &items}
+	}
 
-		if err := rows.Err(); err != nil {
-			resultChan <- model.ChannelListResult[T]{Error: fmt.Errorf("ошибка при обработке результатов: %w", err)}
-		}
+	page.pager.Offset = page.offset
+	if page.size != nil {
+		page.pager.Limit = util.Ptr(*page.size + 1)
+	}
 
-	}()
-    
-    return resultChan
+	return page, nil
 }
 
-func executeRowQueryAsync[T any](db *sql.DB, query string, args []interface{}, 
-    scanFn func(*sql.Row) (T, error)) <-chan model.ChannelItemResult[T] {
-
-	resultChan := make(chan model.ChannelItemResult[T], 1)
+// keysetResult отбрасывает лишнюю строку, восстанавливает порядок при чтении назад
+// и формирует курсоры соседних страниц по значениям ключа граничных строк
+func keysetResult[T any](page keysetPage, items []T, keyFn func(T, string) any) ([]T, model.PageResult) {
+	result := model.PageResult{TotalMode: page.total}
 
-	go func() {
-		var item T
-		row := db.QueryRow(query, args...)
-		item, err := scanFn(row)
-		if err != nil {
-			resultChan <- model.ChannelItemResult[T]{Error: fmt.Errorf("ошибка сканирования строки: %w", err)}
-		}
-		resultChan <- model.ChannelItemResult[T]{Item: &item }
+	hasMore := page.size != nil && len(items) > *page.size
+	if hasMore {
+		items = items[:*page.size]
+	}
 
-	}()
-    
-    return resultChan
-}
+	backward := page.cursor != nil && page.cursor.Prev
+	if backward {
+		slices.Reverse(items)
+	}
 
-func executeGormListQueryAsync[T any](db *gorm.DB, 
-	queryFn func(gdb *gorm.DB) ([]T, error)) <-chan model.ChannelListResult[T] {
-	
-	resultChan := make(chan model.ChannelListResult[T], 1)
+	if len(items) == 0 {
+		return items, result
+	}
 
-	go func() {
-        defer close(resultChan)
+	cursorAt := func(item T, prev bool) *string {
+		keys := util.Map(page.order, func(p model.OrderInfo) any {
+			return keyFn(item, p.Field)
+		})
+		token := util.EncodeCursor(util.Cursor{Sort: util.SortKey(page.order), Keys: keys, Prev: prev})
+		return &token
+	}
 
-		items, err := queryFn(db);
+	// Следующая страница есть, если остались строки после последней или мы пришли назад
+	if (hasMore && !backward) || backward {
+		result.NextCursor = cursorAt(items[len(items)-1], false)
+	}
 
-		resultChan <- model.ChannelListResult[T]{Items: &items, Error: err}
+	// Предыдущая страница есть, если мы пришли вперед по курсору, со смещением или остались строки до первой
+	if (page.cursor != nil && !backward) || (page.offset != nil && *page.offset > 0) || (hasMore && backward) {
+		result.PrevCursor = cursorAt(items[0], true)
+	}
 
-	}()
-    
-    return resultChan
+	return items, result
 }
 
-func executeGormItemQueryAsync[T any](db *gorm.DB, 
-	queryFn func(gdb *gorm.DB) (T, error)) <-chan model.ChannelItemResult[T] {
-	
-	resultChan := make(chan model.ChannelItemResult[T], 1)
+// countRows возвращает общее количество строк способом страницы:
+// exact - выполняет countQuery, estimated - оценку планировщика для selectQuery, none - 0.
+// Для запроса без фильтра оценка берется из статистики таблицы table в pg_class.
+func countRows(ctx context.Context, db *sql.DB, mode model.TotalMode, table string, filtered bool,
+	countQuery string, selectQuery string, args []any) (int, error) {
+
+	switch mode {
+	case model.TotalNone:
+		return 0, nil
+	case model.TotalEstimated:
+		return estimateRows(ctx, db, table, filtered, selectQuery, args)
+	}
 
-	go func() {
-        defer close(resultChan)
+	total, err := executeRowQuery(ctx, db, countQuery, args,
+		func(row *sql.Row) (int, error) {
+			var total int
+			err := row.Scan(&total)
+			return total, err
+		},
+	)
+	if err != nil {
+		return 0, err
+	}
 
-		item, err := queryFn(db);
+	return *total, nil
+}
 
-		resultChan <- model.ChannelItemResult[T]{Item: &item, Error: err}
+// estimateRows возвращает оценку числа строк без полного подсчета
+func estimateRows(ctx context.Context, db *sql.DB, table string, filtered bool, selectQuery string, args []any) (int, error) {
 
-	}()
-    
-    return resultChan
+	if !filtered {
+		// reltuples равен -1, если статистика таблицы еще не собиралась
+		var reltuples float64
+		err := db.QueryRowContext(ctx, queryTableEstimate, table).Scan(&reltuples)
+		if err != nil {
+			return 0, fmt.Errorf("ошибка оценки количества строк %s: %w", table, err)
+		}
+		if reltuples >= 0 {
+			return int(reltuples), nil
+		}
+	}
+
+	var plan []byte
+	err := db.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+selectQuery, args...).Scan(&plan)
+	if err != nil {
+		return 0, fmt.Errorf("ошибка оценки количества строк по плану: %w", err)
+	}
+
+	return planRows(plan)
 }
 
+// planRows извлекает оценку числа строк корневого узла плана EXPLAIN (FORMAT JSON)
+func planRows(plan []byte) (int, error) {
+	var explain []struct {
+		Plan struct {
+			PlanRows float64 `json:"Plan Rows"`
+		} `json:"Plan"`
+	}
+
+	if err := json.Unmarshal(plan, &explain); err != nil || len(explain) == 0 {
+		return 0, fmt.Errorf("неверный план запроса: %s", plan)
+	}
 
+	return int(explain[0].Plan.PlanRows), nil
+}
+
+// inputNames возвращает названия на всех языках из входных данных:
+// названия на русском и английском из отдельных полей дополняют или заменяют карту names
+func inputNames(names map[string]string, ru string, en string) domain.LocalizedText {
+	return domain.LocalizedText(names).With("ru", ru).With("en", en)
+}
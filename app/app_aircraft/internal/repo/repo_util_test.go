@@ -0,0 +1,91 @@
+package repo
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+// TestKeysetResult тестирует обрезку страницы и курсоры соседних страниц
+func TestKeysetResult(t *testing.T) {
+	order := []model.OrderInfo{{Field: "code"}}
+	keyFn := func(code string, field string) any { return code }
+	cursorAt := func(code string, prev bool) *string {
+		token := util.EncodeCursor(util.Cursor{Sort: "code", Keys: []any{code}, Prev: prev})
+		return &token
+	}
+
+	tests := []struct {
+		name  string
+		pager model.PageInfo
+		rows  []string // строки в порядке запроса
+		items []string
+		next  bool
+		prev  bool
+	}{
+		{"Page_FirstOffset", model.PageInfo{Limit: util.Ptr(2)}, []string{"A", "B", "C"}, []string{"A", "B"}, true, false},
+		{"Page_LastOffset", model.PageInfo{Limit: util.Ptr(2), Offset: util.Ptr(2)}, []string{"C"}, []string{"C"}, false, true},
+		{"Page_NextCursor", model.PageInfo{Limit: util.Ptr(2), Cursor: cursorAt("B", false)}, []string{"C", "D", "E"}, []string{"C", "D"}, true, true},
+		{"Page_PrevCursor", model.PageInfo{Limit: util.Ptr(2), Cursor: cursorAt("C", true)}, []string{"B", "A"}, []string{"A", "B"}, true, false},
+		{"Page_PrevCursorMore", model.PageInfo{Limit: util.Ptr(1), Cursor: cursorAt("C", true)}, []string{"B", "A"}, []string{"B"}, true, true},
+		{"Page_Empty", model.PageInfo{Limit: util.Ptr(2), Cursor: cursorAt("Z", false)}, nil, nil, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			page, err := newKeysetPage(tt.pager, order, util.ListFields{"code": {Column: "aircraft_code"}})
+			if err != nil {
+				t.Fatalf("Ошибка разбора страницы: %v", err)
+			}
+
+			if page.pager.Limit == nil || *page.pager.Limit != *tt.pager.Limit+1 {
+				t.Errorf("Ожидался запрос на строку больше размера страницы")
+			}
+
+			items, cursors := keysetResult(page, tt.rows, keyFn)
+
+			if !slices.Equal(items, tt.items) {
+				t.Errorf("Ожидались строки %v, получено %v", tt.items, items)
+			}
+
+			if (cursors.NextCursor != nil) != tt.next || (cursors.PrevCursor != nil) != tt.prev {
+				t.Errorf("Ожидались курсоры next=%v prev=%v, получено %+v", tt.next, tt.prev, cursors)
+			}
+		})
+	}
+}
+
+// TestKeysetPageTotalMode тестирует выбор способа подсчета общего количества
+func TestKeysetPageTotalMode(t *testing.T) {
+	tests := []struct {
+		mode     model.TotalMode
+		expected model.TotalMode
+		failed   bool
+	}{
+		{"", model.TotalExact, false},
+		{model.TotalEstimated, model.TotalEstimated, false},
+		{model.TotalNone, model.TotalNone, false},
+		{"approximate", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			page, err := newKeysetPage(model.PageInfo{TotalMode: tt.mode}, nil, nil)
+
+			if tt.failed {
+				if domain.ErrorKindOf(err) != domain.ErrorValidation {
+					t.Errorf("Ожидалась ошибка валидации, получено %v", err)
+				}
+				return
+			}
+
+			if err != nil || page.total != tt.expected {
+				t.Errorf("Ожидался способ '%v', получено '%v': %v", tt.expected, page.total, err)
+			}
+		})
+	}
+}
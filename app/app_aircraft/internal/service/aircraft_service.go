@@ -1,21 +1,31 @@
 package service
 
 import (
-	"log"
-    "fmt"
+    "context"
+
     "github.com/snpavlov/app_aircraft/internal/conf"
+    "github.com/snpavlov/app_aircraft/internal/domain"
+    "github.com/snpavlov/app_aircraft/internal/i18n"
     "github.com/snpavlov/app_aircraft/internal/repo"
 	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
 )
 
 
 // Определяем интерфейс репозитория IAircraftRepo
 type IAircraftService interface {
-	GetAircrafts(pager model.PageInfo) (model.ServiceListResult[model.AircraftData], error)
-	GetAircraftByCode(code string) (model.ServiceDataResult[model.AircraftData], error)
-   	CreateAircraft(input model.AircraftInput) (model.ServiceDataResult[model.AircraftData], error) 
-	UpdateAircraft(input model.AircraftInput) (model.ServiceDataResult[model.AircraftData], error) 
-	DeleteAircraft(code string) (model.ServiceDataResult[string], error) 
+	GetAircrafts(ctx context.Context, pager model.PageInfo, query model.ListQuery) (model.ServiceListResult[model.AircraftData], error)
+	GetAircraftByCode(ctx context.Context, code string) (model.ServiceDataResult[model.AircraftData], error)
+   	CreateAircraft(ctx context.Context, input model.AircraftInput) (model.ServiceDataResult[model.AircraftData], error) 
+	UpdateAircraft(ctx context.Context, input model.AircraftInput, ifMatch string) (model.ServiceDataResult[model.AircraftData], error) 
+	PatchAircraft(ctx context.Context, code string, patch []byte, ifMatch string) (model.ServiceDataResult[model.AircraftData], error)
+	DeleteAircraft(ctx context.Context, code string, input model.DeleteAircraftInput, ifMatch string) (model.ServiceDataResult[string], error) 
+
+	GetAircraftSeats(ctx context.Context, code string) (model.ServiceDataResult[model.AircraftSeatsData], error)
+	CreateSeat(ctx context.Context, input model.SeatInput) (model.ServiceDataResult[model.AircraftSeatsData], error)
+	UpdateSeat(ctx context.Context, input model.SeatInput) (model.ServiceDataResult[model.AircraftSeatsData], error)
+	DeleteSeat(ctx context.Context, code string, seatNo string) (model.ServiceDataResult[model.AircraftSeatsData], error)
+	ReplaceAircraftLayout(ctx context.Context, code string, input model.LayoutInput) (model.ServiceDataResult[model.AircraftLayoutData], error)
 }
 
 type AircraftService struct {
@@ -24,153 +34,444 @@ type AircraftService struct {
 
 func (service AircraftService) NewAircraftService(config conf.IConfiguration) (IAircraftService, error) {
        
-    // создать экземпляр репозитория
-    service.Repo = repo.AircraftSqlRepo{Configuration: config};
+    // создать экземпляр репозитория с общим пулом соединений
+    aircraftRepo, err := repo.AircraftSqlRepo{}.NewAircraftSqlRepo(config)
+    if err != nil {
+        return nil, err
+    }
+
+    service.Repo = aircraftRepo
 
 	return service, nil
 }
 
-func (service AircraftService) GetAircrafts(pager model.PageInfo) (model.ServiceListResult[model.AircraftData], error) {
+func (service AircraftService) GetAircrafts(ctx context.Context, pager model.PageInfo, query model.ListQuery) (model.ServiceListResult[model.AircraftData], error) {
+
+	options, validations := util.ParseListQuery(query, repo.AircraftListFields)
+	if len(validations) > 0 {
+		return model.ServiceListResult[model.AircraftData]{},
+			domain.NewValidationError(i18n.MsgListInvalid, validations...)
+	}
 
 	db, err := service.Repo.GetDBConnection()
     if err != nil {
-        log.Fatalf("Не удалось подключиться к базе данных: %v", err)
-        return model.ServiceListResult[model.AircraftData]{}, err
+        return model.ServiceListResult[model.AircraftData]{}, repoError("GetDBConnection", err)
     }
-    defer db.Close()
 
-	data, total, err := service.Repo.GetAircraftItemsAsync(db, pager)
+	var data []model.AircraftData
+	var total int
+	var pageResult model.PageResult
+
+	fetch := func(pager model.PageInfo, options model.ListOptions) ([]model.AircraftData, int, model.PageResult, error) {
+		return service.Repo.GetAircraftItemsAsync(ctx, db, pager, options)
+	}
+
+	if query.Q != "" {
+		// Поиск ограничивает список найденными самолетами
+		names, nameErr := service.Repo.GetAircraftNames(ctx, db)
+		if nameErr != nil {
+			return model.ServiceListResult[model.AircraftData]{}, repoError("GetAircraftNames", nameErr)
+		}
+
+		ranks := util.RankSearch(query.Q, aircraftCandidates(names))
+		data, total, pageResult, err = searchList(ranks, pager, options, fetch,
+			func(p model.AircraftData) string { return p.Code })
+	} else {
+		data, total, pageResult, err = fetch(pager, options)
+	}
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetAircraftItems': %v", err)
-        return model.ServiceListResult[model.AircraftData]{}, err
+        return model.ServiceListResult[model.AircraftData]{}, repoError("GetAircraftItems", err)
     }
 
-	result := model.ServiceListResult[model.AircraftData] { Result: true, Total: total, Items: &data }
+	setAircraftVersions(data)
+
+	result := model.ServiceListResult[model.AircraftData] { Result: true, Total: total, Items: &data,
+		NextCursor: pageResult.NextCursor, PrevCursor: pageResult.PrevCursor, TotalMode: pageResult.TotalMode }
 
 	return result, nil
 }
 
-func (service AircraftService) GetAircraftByCode(code string) (model.ServiceDataResult[model.AircraftData], error) {
+func (service AircraftService) GetAircraftByCode(ctx context.Context, code string) (model.ServiceDataResult[model.AircraftData], error) {
 
 	db, err := service.Repo.GetDBConnection()
     if err != nil {
-        log.Fatalf("Не удалось подключиться к базе данных: %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftData]{}, repoError("GetDBConnection", err)
     }
-    defer db.Close()
 
-	data, err := service.Repo.GetAircraftItemByCodeAsync(db, code)
+	data, err := service.Repo.GetAircraftItemByCodeAsync(ctx, db, code)
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetAircrafts': %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftData]{}, repoError("GetAircraftItemByCode", err)
     }
 
+    if (data == nil) {
+        return model.ServiceDataResult[model.AircraftData]{}, domain.NewNotFoundError(i18n.MsgAircraftNotFound, code)
+    }
+
+    data.Version = aircraftVersion(*data)
+
 	result := model.ServiceDataResult[model.AircraftData] { Result: true, Data: data }
 
 	return result, nil
 }
 
-func (service AircraftService) CreateAircraft(input model.AircraftInput) (model.ServiceDataResult[model.AircraftData], error) {
+func (service AircraftService) CreateAircraft(ctx context.Context, input model.AircraftInput) (model.ServiceDataResult[model.AircraftData], error) {
 	
-    db, err := service.Repo.GetDBConnection()
+    if validations := util.ValidateInput(input); len(validations) > 0 {
+        return model.ServiceDataResult[model.AircraftData]{}, domain.NewValidationError(i18n.MsgInputInvalid, validations...)
+    }
+
+    uow, err := service.Repo.UnitOfWork()
     if err != nil {
-        log.Fatalf("Не удалось подключиться к базе данных: %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftData]{}, repoError("UnitOfWork", err)
     }
-    defer db.Close()
 
-    exists, err := service.Repo.GetExistsByCode(db, input.Code) 
+    // Проверка, вставка и чтение созданной записи выполняются в одной транзакции
+    var data *model.AircraftData
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        exists, err := service.Repo.GetExistsByCode(ctx, tx, input.Code) 
+        if err != nil {
+            return err
+        }
+
+        if (exists) {
+            return domain.NewConflictError(i18n.MsgAircraftExists, input.Code)
+        }
+
+        data, err = service.Repo.CreateAircraft(ctx, tx, input)
+        return err
+    })
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetExistsByCode': %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftData]{}, repoError("CreateAircraft", err)
     }
 
-    if (exists) {
-        result := model.ServiceDataResult[model.AircraftData] { 
-            Result: false, 
-            Message: fmt.Sprintf("Самолет с кодом '%v' уже существует!", input.Code),
-         }
-        return result, nil
+    data.Version = aircraftVersion(*data)
+
+	result := model.ServiceDataResult[model.AircraftData] { Result: true, Data: data }
+
+	return result, nil
+    
+}
+
+
+// UpdateAircraft изменяет самолет; непустое условие ifMatch сравнивается с версией самолета в той же транзакции
+func (service AircraftService) UpdateAircraft(ctx context.Context, input model.AircraftInput, ifMatch string) (model.ServiceDataResult[model.AircraftData], error) {
+	
+    if validations := util.ValidateInput(input); len(validations) > 0 {
+        return model.ServiceDataResult[model.AircraftData]{}, domain.NewValidationError(i18n.MsgInputInvalid, validations...)
     }
 
-    data, err := service.Repo.CreateAircraft(db, input)
+    uow, err := service.Repo.UnitOfWork()
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'CreateAircraft': %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftData]{}, repoError("UnitOfWork", err)
     }
 
+    var data *model.AircraftData
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        exists, err := service.Repo.GetExistsByCode(ctx, tx, input.Code) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgAircraftNotFound, input.Code)
+        }
+
+        if err := service.checkAircraftVersion(ctx, tx, input.Code, ifMatch); err != nil {
+            return err
+        }
+
+        data, err = service.Repo.UpdateAircraft(ctx, tx, input)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftData]{}, repoError("UpdateAircraft", err)
+    }
+
+    data.Version = aircraftVersion(*data)
+
 	result := model.ServiceDataResult[model.AircraftData] { Result: true, Data: data }
 
 	return result, nil
     
 }
 
-func (service AircraftService) UpdateAircraft(input model.AircraftInput) (model.ServiceDataResult[model.AircraftData], error) {
+
+// DeleteAircraft удаляет самолет, если на него не ссылаются рейсы. Места удаляются вместе с самолетом
+// только в режиме cascade=seats, иначе удаление отклоняется с перечнем зависимых записей.
+// Непустое условие ifMatch сравнивается с версией самолета в той же транзакции.
+func (service AircraftService) DeleteAircraft(ctx context.Context, code string, input model.DeleteAircraftInput, ifMatch string) (model.ServiceDataResult[string], error) {
 	
+    cascade := input.Cascade == model.CascadeSeats
+    if input.Cascade != "" && !cascade {
+        return model.ServiceDataResult[string]{}, domain.NewArgumentError(i18n.MsgRequestCascade, nil, input.Cascade)
+    }
+
+    uow, err := service.Repo.UnitOfWork()
+    if err != nil {
+        return model.ServiceDataResult[string]{}, repoError("UnitOfWork", err)
+    }
+
+    var data *string
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        exists, err := service.Repo.GetExistsByCode(ctx, tx, code) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgAircraftNotFound, code)
+        }
+
+        if err := service.checkAircraftVersion(ctx, tx, code, ifMatch); err != nil {
+            return err
+        }
+
+        dependencies, err := service.Repo.GetAircraftDependencies(ctx, tx, code)
+        if err != nil {
+            return err
+        }
+
+        if blocking := aircraftDependencies(*dependencies, cascade); len(blocking) > 0 {
+            return domain.NewInUseError(i18n.MsgAircraftInUse, blocking, code)
+        }
+
+        if (cascade && dependencies.Seats > 0) {
+            if _, err := service.Repo.DeleteAircraftSeats(ctx, tx, code); err != nil {
+                return err
+            }
+        }
+
+        data, err = service.Repo.DeleteAircraft(ctx, tx, code)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[string]{}, repoError("DeleteAircraft", err)
+    }
+
+	result := model.ServiceDataResult[string] { Result: true, Data: data }
+
+	return result, nil
+    
+}
+
+// aircraftDependencies возвращает записи, которые не дают удалить самолет:
+// рейсы всегда, места - если они не удаляются каскадом
+func aircraftDependencies(dependencies model.AircraftDependencies, cascade bool) []model.Validation {
+	var blocking []model.Validation
+
+	if dependencies.Seats > 0 && !cascade {
+		blocking = append(blocking, i18n.Validation("seats", i18n.MsgDependencySeats, dependencies.Seats))
+	}
+	if dependencies.FutureFlights > 0 {
+		blocking = append(blocking, i18n.Validation("futureFlights", i18n.MsgDependencyFuture, dependencies.FutureFlights))
+	}
+	if dependencies.PastFlights > 0 {
+		blocking = append(blocking, i18n.Validation("pastFlights", i18n.MsgDependencyPast, dependencies.PastFlights))
+	}
+
+	return blocking
+}
+
+
+func (service AircraftService) GetAircraftSeats(ctx context.Context, code string) (model.ServiceDataResult[model.AircraftSeatsData], error) {
+
     db, err := service.Repo.GetDBConnection()
     if err != nil {
-        log.Fatalf("Не удалось подключиться к базе данных: %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("GetDBConnection", err)
     }
-    defer db.Close()
 
-    exists, err := service.Repo.GetExistsByCode(db, input.Code) 
+    exists, err := service.Repo.GetExistsByCode(ctx, db, code) 
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetExistsByCode': %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("GetExistsByCode", err)
     }
 
     if (!exists) {
-        result := model.ServiceDataResult[model.AircraftData] { 
-            Result: false, 
-            Message: fmt.Sprintf("Самолет с кодом '%v' не существует!", input.Code),
-         }
-        return result, nil
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, domain.NewNotFoundError(i18n.MsgAircraftNotFound, code)
     }
 
-    data, err := service.Repo.UpdateAircraft(db, input)
+    data, err := service.Repo.GetSeatItems(ctx, db, code)
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'CreateAircraft': %v", err)
-        return model.ServiceDataResult[model.AircraftData]{}, err
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("GetSeatItems", err)
     }
 
-	result := model.ServiceDataResult[model.AircraftData] { Result: true, Data: data }
+	result := model.ServiceDataResult[model.AircraftSeatsData] { Result: true, Data: data }
 
 	return result, nil
-    
 }
 
-func (service AircraftService) DeleteAircraft(code string) (model.ServiceDataResult[string], error) {
-	
-    db, err := service.Repo.GetDBConnection()
+func (service AircraftService) CreateSeat(ctx context.Context, input model.SeatInput) (model.ServiceDataResult[model.AircraftSeatsData], error) {
+
+    if validations := util.ValidateInput(input); len(validations) > 0 {
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, domain.NewValidationError(i18n.MsgInputInvalid, validations...)
+    }
+
+    uow, err := service.Repo.UnitOfWork()
     if err != nil {
-        log.Fatalf("Не удалось подключиться к базе данных: %v", err)
-        return model.ServiceDataResult[string]{}, err
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("UnitOfWork", err)
     }
-    defer db.Close()
 
-    exists, err := service.Repo.GetExistsByCode(db, code) 
+    var data *model.AircraftSeatsData
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        exists, err := service.Repo.GetExistsByCode(ctx, tx, input.Code) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgAircraftNotFound, input.Code)
+        }
+
+        exists, err = service.Repo.GetSeatExists(ctx, tx, input.Code, input.SeatNumb) 
+        if err != nil {
+            return err
+        }
+
+        if (exists) {
+            return domain.NewConflictError(i18n.MsgSeatExists, input.SeatNumb, input.Code)
+        }
+
+        data, err = service.Repo.CreateSeat(ctx, tx, input)
+        return err
+    })
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetExistsByCode': %v", err)
-        return model.ServiceDataResult[string]{}, err
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("CreateSeat", err)
     }
 
-    if (!exists) {
-        result := model.ServiceDataResult[string] { 
-            Result: false, 
-            Message: fmt.Sprintf("Самолет с кодом '%v' не существует!", code),
-         }
-        return result, nil
+	result := model.ServiceDataResult[model.AircraftSeatsData] { Result: true, Data: data }
+
+	return result, nil
+}
+
+
+func (service AircraftService) UpdateSeat(ctx context.Context, input model.SeatInput) (model.ServiceDataResult[model.AircraftSeatsData], error) {
+
+    if validations := util.ValidateInput(input); len(validations) > 0 {
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, domain.NewValidationError(i18n.MsgInputInvalid, validations...)
     }
 
-    data, err := service.Repo.DeleteAircraft(db, code)
+    uow, err := service.Repo.UnitOfWork()
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'DeleteAircraft': %v", err)
-        return model.ServiceDataResult[string]{}, err
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("UnitOfWork", err)
     }
 
-	result := model.ServiceDataResult[string] { Result: true, Data: data }
+    var data *model.AircraftSeatsData
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        exists, err := service.Repo.GetSeatExists(ctx, tx, input.Code, input.SeatNumb) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgSeatNotFound, input.SeatNumb, input.Code)
+        }
+
+        data, err = service.Repo.UpdateSeat(ctx, tx, input)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("UpdateSeat", err)
+    }
+
+	result := model.ServiceDataResult[model.AircraftSeatsData] { Result: true, Data: data }
 
 	return result, nil
-    
-}
\ No newline at end of file
+}
+
+
+func (service AircraftService) DeleteSeat(ctx context.Context, code string, seatNo string) (model.ServiceDataResult[model.AircraftSeatsData], error) {
+
+    uow, err := service.Repo.UnitOfWork()
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("UnitOfWork", err)
+    }
+
+    var data *model.AircraftSeatsData
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        exists, err := service.Repo.GetSeatExists(ctx, tx, code, seatNo) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgSeatNotFound, seatNo, code)
+        }
+
+        data, err = service.Repo.DeleteSeat(ctx, tx, code, seatNo)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftSeatsData]{}, repoError("DeleteSeat", err)
+    }
+
+	result := model.ServiceDataResult[model.AircraftSeatsData] { Result: true, Data: data }
+
+	return result, nil
+}
+
+
+// ReplaceAircraftLayout заменяет схему мест самолета. Проверка самолета, чтение текущих мест, сравнение
+// и замена выполняются в одной транзакции, а строка самолета блокируется, чтобы параллельная замена
+// или удаление не изменили места между сравнением и записью.
+func (service AircraftService) ReplaceAircraftLayout(ctx context.Context, code string, input model.LayoutInput) (model.ServiceDataResult[model.AircraftLayoutData], error) {
+
+    seats, err := util.ParseSeatLayout(input.Layout)
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftLayoutData]{}, domain.NewValidationError(i18n.MsgLayoutInvalid,
+            i18n.ValidationOf("layout", err))
+    }
+
+    uow, err := service.Repo.UnitOfWork()
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftLayoutData]{}, repoError("UnitOfWork", err)
+    }
+
+    var data model.AircraftLayoutData
+    err = uow.Do(ctx, func(tx repo.DBTX) error {
+        aircraft, err := service.Repo.GetAircraftForUpdate(ctx, tx, code)
+        if err != nil {
+            return err
+        }
+
+        if (aircraft == nil) {
+            return domain.NewNotFoundError(i18n.MsgAircraftNotFound, code)
+        }
+
+        current, err := service.Repo.GetSeatItems(ctx, tx, code)
+        if err != nil {
+            return err
+        }
+
+        added, removed, changed := util.DiffSeatLayout(*current.Items, seats)
+        seatTypes := util.CountSeatTypes(seats)
+
+        data = model.AircraftLayoutData{
+            Code: code,
+            DryRun: input.DryRun,
+            SeatCount: len(seats),
+            Seats: &seatTypes,
+            Added: &added,
+            Removed: &removed,
+            Changed: &changed,
+        }
+
+        // В режиме предварительного просмотра места не изменяются
+        if (input.DryRun) {
+            return nil
+        }
+
+        replaced, err := service.Repo.ReplaceSeatLayout(ctx, tx, code, seats)
+        if err != nil {
+            return err
+        }
+        data.SeatCount = replaced.SeatCount
+        data.Seats = replaced.Seats
+        return nil
+    })
+    if err != nil {
+        return model.ServiceDataResult[model.AircraftLayoutData]{}, repoError("ReplaceAircraftLayout", err)
+    }
+
+	result := model.ServiceDataResult[model.AircraftLayoutData] { Result: true, Data: &data }
+
+	return result, nil
+}
@@ -1,10 +1,19 @@
 package service
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
     "testing"
 	"log"
+
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/snpavlov/app_aircraft/internal/conf"
+	"github.com/snpavlov/app_aircraft/internal/domain"
 	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/util"
 )
 
 // TestGetAircrafts тестирует получение самолетов
@@ -30,7 +39,7 @@ func TestService_GetAircrafts(t *testing.T) {
         Offset: nil, // Без смещения
     }
 
-	result, err := service.GetAircrafts(pager)
+	result, err := service.GetAircrafts(context.Background(), pager, model.ListQuery{})
 
 	if err != nil {
 		t.Errorf("Ошибка запроса данных 'GetAircrafts': %v", err)
@@ -59,7 +68,7 @@ func TestService_GetAircraftByCodeTest(t *testing.T) {
 
 	codeOk := "SU9"
 
-	result, err := service.GetAircraftByCode(codeOk)
+	result, err := service.GetAircraftByCode(context.Background(), codeOk)
 
 	if err != nil {
 		t.Errorf("Ошибка запроса данных 'GetAircrafts': %v", err)
@@ -70,3 +79,259 @@ func TestService_GetAircraftByCodeTest(t *testing.T) {
 
 }
 
+// Репозиторий самолетов для операций записи: проверки и запись выполняются через подключение единицы работы
+type aircraftWriteStub struct {
+	repo.IAircraftRepo
+	exists       bool
+	write        error
+	tx           *repo.DBTX
+	dependencies model.AircraftDependencies
+	deleted      *[]string
+	current      *model.AircraftData
+	seats        []model.SeatItemData
+	layout       *[]model.SeatItemData
+}
+
+func (stub aircraftWriteStub) UnitOfWork() (repo.IUnitOfWork[repo.DBTX], error) {
+	return unitOfWorkStub[repo.DBTX]{tx: &sql.Tx{}}, nil
+}
+
+func (stub aircraftWriteStub) GetExistsByCode(ctx context.Context, db repo.DBTX, code string) (bool, error) {
+	*stub.tx = db
+	return stub.exists, nil
+}
+
+func (stub aircraftWriteStub) GetSeatExists(ctx context.Context, db repo.DBTX, code string, seatNo string) (bool, error) {
+	return false, nil
+}
+
+func (stub aircraftWriteStub) CreateAircraft(ctx context.Context, db repo.DBTX, input model.AircraftInput) (*model.AircraftData, error) {
+	if db != *stub.tx {
+		return nil, errors.New("запись выполняется вне транзакции проверки")
+	}
+	return &model.AircraftData{Code: input.Code}, stub.write
+}
+
+func (stub aircraftWriteStub) CreateSeat(ctx context.Context, db repo.DBTX, input model.SeatInput) (*model.AircraftSeatsData, error) {
+	return nil, stub.write
+}
+
+func (stub aircraftWriteStub) GetAircraftForUpdate(ctx context.Context, db repo.DBTX, code string) (*model.AircraftData, error) {
+	*stub.tx = db
+	return stub.current, nil
+}
+
+func (stub aircraftWriteStub) GetSeatItems(ctx context.Context, db repo.DBTX, code string) (*model.AircraftSeatsData, error) {
+	if db != *stub.tx {
+		return nil, errors.New("чтение мест выполняется вне транзакции проверки")
+	}
+	return &model.AircraftSeatsData{Code: code, SeatCount: len(stub.seats), Items: &stub.seats}, nil
+}
+
+func (stub aircraftWriteStub) ReplaceSeatLayout(ctx context.Context, db repo.DBTX, code string, seats []model.SeatItemData) (*model.AircraftSeatsData, error) {
+	if db != *stub.tx {
+		return nil, errors.New("замена мест выполняется вне транзакции проверки")
+	}
+	*stub.layout = seats
+	return &model.AircraftSeatsData{Code: code, SeatCount: len(seats)}, stub.write
+}
+
+func (stub aircraftWriteStub) UpdateAircraft(ctx context.Context, db repo.DBTX, input model.AircraftInput) (*model.AircraftData, error) {
+	return &model.AircraftData{Code: input.Code, NameRu: input.NameRu, NameEn: input.NameEn, Range: input.Range}, stub.write
+}
+
+func (stub aircraftWriteStub) GetAircraftDependencies(ctx context.Context, db repo.DBTX, code string) (*model.AircraftDependencies, error) {
+	return &stub.dependencies, nil
+}
+
+func (stub aircraftWriteStub) DeleteAircraftSeats(ctx context.Context, db repo.DBTX, code string) (int64, error) {
+	*stub.deleted = append(*stub.deleted, "seats")
+	return int64(stub.dependencies.Seats), nil
+}
+
+func (stub aircraftWriteStub) DeleteAircraft(ctx context.Context, db repo.DBTX, code string) (*string, error) {
+	*stub.deleted = append(*stub.deleted, "aircraft")
+	return &code, stub.write
+}
+
+// TestService_AircraftWrites тестирует запись самолетов в единице работы и ошибки ограничений базы данных
+func TestService_AircraftWrites(t *testing.T) {
+	aircraft := model.AircraftInput{Code: "77W", NameRu: "Боинг 777-300ER", NameEn: "Boeing 777-300ER", Range: 14600}
+	seat := model.SeatInput{Code: "77W", SeatNumb: "1A", SeatType: "Business"}
+
+	cascade := model.DeleteAircraftInput{Cascade: model.CascadeSeats}
+	current := model.AircraftData{Code: "77W", NameRu: "Боинг 777-300ER", NameEn: "Boeing 777-300ER", Range: 13650}
+	updateAircraft := func(ifMatch string) func(service IAircraftService) error {
+		return func(service IAircraftService) error {
+			_, err := service.UpdateAircraft(context.Background(), aircraft, ifMatch)
+			return err
+		}
+	}
+	deleteAircraft := func(input model.DeleteAircraftInput, ifMatch string) func(service IAircraftService) error {
+		return func(service IAircraftService) error {
+			_, err := service.DeleteAircraft(context.Background(), "77W", input, ifMatch)
+			return err
+		}
+	}
+
+	tests := []struct {
+		name         string
+		stub         aircraftWriteStub
+		call         func(service IAircraftService) error
+		expected     *domain.ErrorKind
+		dependencies []string
+		deleted      []string
+	}{
+		{"CreateAircraft", aircraftWriteStub{},
+			func(service IAircraftService) error { _, err := service.CreateAircraft(context.Background(), aircraft); return err },
+			nil, nil, nil},
+		{"CreateAircraft_Exists", aircraftWriteStub{exists: true},
+			func(service IAircraftService) error { _, err := service.CreateAircraft(context.Background(), aircraft); return err },
+			util.Ptr(domain.ErrorConflict), nil, nil},
+		{"CreateAircraft_UniqueViolation", aircraftWriteStub{write: &pgconn.PgError{Code: "23505", ConstraintName: "aircrafts_pkey"}},
+			func(service IAircraftService) error { _, err := service.CreateAircraft(context.Background(), aircraft); return err },
+			util.Ptr(domain.ErrorConflict), nil, nil},
+		{"CreateSeat_ForeignKeyViolation", aircraftWriteStub{exists: true, write: &pgconn.PgError{Code: "23503", ConstraintName: "seats_aircraft_code_fkey"}},
+			func(service IAircraftService) error { _, err := service.CreateSeat(context.Background(), seat); return err },
+			util.Ptr(domain.ErrorReference), nil, nil},
+		{"DeleteAircraft", aircraftWriteStub{exists: true},
+			deleteAircraft(model.DeleteAircraftInput{}, ""), nil, nil, []string{"aircraft"}},
+		{"DeleteAircraft_HasSeats", aircraftWriteStub{exists: true, dependencies: model.AircraftDependencies{Seats: 402}},
+			deleteAircraft(model.DeleteAircraftInput{}, ""), util.Ptr(domain.ErrorConflict), []string{"seats"}, nil},
+		{"DeleteAircraft_CascadeSeats", aircraftWriteStub{exists: true, dependencies: model.AircraftDependencies{Seats: 402}},
+			deleteAircraft(cascade, ""), nil, nil, []string{"seats", "aircraft"}},
+		{"DeleteAircraft_CascadeHasFlights", aircraftWriteStub{exists: true, dependencies: model.AircraftDependencies{Seats: 402, FutureFlights: 3, PastFlights: 12}},
+			deleteAircraft(cascade, ""), util.Ptr(domain.ErrorConflict), []string{"futureFlights", "pastFlights"}, nil},
+		{"DeleteAircraft_HasFlights", aircraftWriteStub{exists: true, dependencies: model.AircraftDependencies{Seats: 402, PastFlights: 12}},
+			deleteAircraft(model.DeleteAircraftInput{}, ""), util.Ptr(domain.ErrorConflict), []string{"seats", "pastFlights"}, nil},
+		{"UpdateAircraft_IfMatch", aircraftWriteStub{exists: true, current: &current},
+			updateAircraft(util.ETag(aircraftVersion(current))), nil, nil, nil},
+		{"UpdateAircraft_IfMatchAny", aircraftWriteStub{exists: true, current: &current},
+			updateAircraft("*"), nil, nil, nil},
+		{"UpdateAircraft_VersionMismatch", aircraftWriteStub{exists: true, current: &current},
+			updateAircraft(`"0000"`), util.Ptr(domain.ErrorPrecondition), nil, nil},
+		{"DeleteAircraft_VersionMismatch", aircraftWriteStub{exists: true, current: &current},
+			deleteAircraft(model.DeleteAircraftInput{}, util.ETag(aircraftVersion(model.AircraftData{Code: "77W"}))),
+			util.Ptr(domain.ErrorPrecondition), nil, nil},
+		{"DeleteAircraft_UnknownCascade", aircraftWriteStub{exists: true},
+			deleteAircraft(model.DeleteAircraftInput{Cascade: "flights"}, ""), util.Ptr(domain.ErrorArgument), nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stub.tx = new(repo.DBTX)
+			tt.stub.deleted = new([]string)
+
+			err := tt.call(AircraftService{Repo: tt.stub})
+
+			if !reflect.DeepEqual(*tt.stub.deleted, tt.deleted) {
+				t.Errorf("Ожидалось удаление %v, выполнено %v", tt.deleted, *tt.stub.deleted)
+			}
+
+			if tt.expected == nil {
+				if err != nil {
+					t.Fatalf("Неожиданная ошибка: %v", err)
+				}
+				return
+			}
+
+			serr := domain.AsServiceError(err)
+			if serr.Kind != *tt.expected {
+				t.Errorf("Ожидалась ошибка вида '%v', получена '%v': %v", *tt.expected, serr.Kind, err)
+			}
+
+			// Отказ в удалении перечисляет зависимые записи
+			properties := util.Map(serr.Validations, func(p model.Validation) string { return p.Property })
+			if len(tt.dependencies) > 0 && !reflect.DeepEqual(properties, tt.dependencies) {
+				t.Errorf("Ожидались зависимости %v, получено %v", tt.dependencies, properties)
+			}
+		})
+	}
+}
+
+// TestService_ReplaceAircraftLayout тестирует замену схемы мест: проверка самолета, чтение текущих мест
+// и замена выполняются в одной транзакции, а в режиме предварительного просмотра места не изменяются
+func TestService_ReplaceAircraftLayout(t *testing.T) {
+	current := model.AircraftData{Code: "CR2"}
+	seats := []model.SeatItemData{{SeatNo: "1A", SeatType: "Business"}, {SeatNo: "1C", SeatType: "Business"},
+		{SeatNo: "2A", SeatType: "Economy"}}
+	layout := "Business rows 1 A; Economy rows 2 A,C"
+
+	tests := []struct {
+		name     string
+		stub     aircraftWriteStub
+		input    model.LayoutInput
+		expected *domain.ErrorKind
+		replaced int
+	}{
+		{"Replace", aircraftWriteStub{current: &current, seats: seats}, model.LayoutInput{Layout: layout}, nil, 3},
+		{"DryRun", aircraftWriteStub{current: &current, seats: seats}, model.LayoutInput{Layout: layout, DryRun: true}, nil, 0},
+		{"NotFound", aircraftWriteStub{}, model.LayoutInput{Layout: layout}, util.Ptr(domain.ErrorNotFound), 0},
+		{"InvalidLayout", aircraftWriteStub{current: &current}, model.LayoutInput{Layout: "First rows 1 A"},
+			util.Ptr(domain.ErrorValidation), 0},
+		{"ForeignKeyViolation", aircraftWriteStub{current: &current, seats: seats,
+			write: &pgconn.PgError{Code: "23503", ConstraintName: "seats_aircraft_code_fkey"}},
+			model.LayoutInput{Layout: layout}, util.Ptr(domain.ErrorReference), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.stub.tx = new(repo.DBTX)
+			tt.stub.layout = new([]model.SeatItemData)
+
+			result, err := AircraftService{Repo: tt.stub}.ReplaceAircraftLayout(context.Background(), "CR2", tt.input)
+
+			if replaced := len(*tt.stub.layout); replaced != tt.replaced {
+				t.Errorf("Ожидалась замена %d мест, заменено %d", tt.replaced, replaced)
+			}
+
+			if tt.expected != nil {
+				if kind := domain.ErrorKindOf(err); kind != *tt.expected {
+					t.Errorf("Ожидалась ошибка вида '%v', получена '%v': %v", *tt.expected, kind, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+
+			data := result.Data
+			if len(*data.Added) != 1 || len(*data.Removed) != 1 || len(*data.Changed) != 0 || data.SeatCount != 3 {
+				t.Errorf("Неверное сравнение схем: добавлено %v, удалено %v, изменено %v", *data.Added, *data.Removed, *data.Changed)
+			}
+		})
+	}
+}
+
+// TestService_AircraftInputValidation тестирует проверку входных данных самолета и места до обращения к репозиторию:
+// сервис без репозитория возвращает ошибку валидации, а не панику
+func TestService_AircraftInputValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"CreateAircraft", func() error {
+			_, err := AircraftService{}.CreateAircraft(context.Background(), model.AircraftInput{Code: "7730000000", Range: -1})
+			return err
+		}},
+		{"UpdateSeat", func() error {
+			_, err := AircraftService{}.UpdateSeat(context.Background(), model.SeatInput{Code: "773", SeatType: "First", SeatNumb: "1A"})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var serr *domain.ServiceError
+			if err := tt.call(); !errors.As(err, &serr) || serr.Kind != domain.ErrorValidation || len(serr.Validations) == 0 {
+				t.Fatalf("Ожидалась ошибка валидации, получено %v", err)
+			}
+			for _, validation := range serr.Validations {
+				if validation.Property == "" {
+					t.Errorf("Не задано свойство для '%s'", validation.Message)
+				}
+			}
+		})
+	}
+}
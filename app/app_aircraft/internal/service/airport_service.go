@@ -1,58 +1,365 @@
 package service
 
 import (
-	"log"
+    "context"
+    "slices"
+    "strings"
 
     "github.com/snpavlov/app_aircraft/internal/conf"
+    "github.com/snpavlov/app_aircraft/internal/domain"
+    "github.com/snpavlov/app_aircraft/internal/i18n"
+    "github.com/snpavlov/app_aircraft/internal/geo"
     "github.com/snpavlov/app_aircraft/internal/repo"
 	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
 )
 
 
 // Определяем интерфейс репозитория IAircraftRepo
 type IAirportService interface {
-	GetAirports(pager model.PageInfo) (model.ServiceListResult[model.AirportData], error)
-	GetAirportByCode(code string) (model.ServiceDataResult[model.AirportData], error)
-   	// CreateAirport(input model.AircraftInput) (model.ServiceDataResult[model.AirportData], error) 
-	// UpdateAirport(input model.AircraftInput) (model.ServiceDataResult[model.AirportData], error) 
-	// DeleteAirport(code string) (model.ServiceDataResult[string], error) 
+	GetAirports(ctx context.Context, pager model.PageInfo, query model.ListQuery) (model.ServiceListResult[model.AirportData], error)
+	GetAirportByCode(ctx context.Context, code string) (model.ServiceDataResult[model.AirportData], error)
+   	CreateAirport(ctx context.Context, input model.AirportInput) (model.ServiceDataResult[model.AirportData], error) 
+	UpdateAirport(ctx context.Context, input model.AirportInput, ifMatch string) (model.ServiceDataResult[model.AirportData], error) 
+	PatchAirport(ctx context.Context, code string, patch []byte, ifMatch string) (model.ServiceDataResult[model.AirportData], error)
+	DeleteAirport(ctx context.Context, code string, ifMatch string) (model.ServiceDataResult[string], error) 
+	GetNearbyAirports(ctx context.Context, input model.GeoInput) (model.ServiceListResult[model.AirportGeoData], error)
 }
 
+// Размер выдачи ближайших аэропортов по умолчанию и наибольший размер выдачи.
+// Отбор по радиусу или прямоугольнику без размера выдается до наибольшего размера, чтобы не терять аэропорты области.
+const (
+	nearbyDefaultSize = 10
+	nearbyMaxSize     = 100
+)
+
 type AirportService struct {
     Repo repo.IAirportRepo
 }
 
 func (service AirportService) NewAirportService(config conf.IConfiguration) (IAirportService, error) {
        
-    // создать экземпляр репозитория
-    service.Repo = repo.GormDBContext{Configuration: config};
+    // создать экземпляр репозитория с общим пулом соединений
+    gormContext, err := repo.GormDBContext{}.NewGormDBContext(config)
+    if err != nil {
+        return nil, err
+    }
+
+    service.Repo = gormContext
 
 	return service, nil
 }
 
-func (service AirportService) GetAirports(pager model.PageInfo) (model.ServiceListResult[model.AirportData], error) {
+func (service AirportService) GetAirports(ctx context.Context, pager model.PageInfo, query model.ListQuery) (model.ServiceListResult[model.AirportData], error) {
+
+	options, validations := util.ParseListQuery(query, repo.AirportListFields)
+	if len(validations) > 0 {
+		return model.ServiceListResult[model.AirportData]{},
+			domain.NewValidationError(i18n.MsgListInvalid, validations...)
+	}
 
-	data, total, err := service.Repo.GetAitportItems(pager)
+	var data []model.AirportData
+	var total int
+	var pageResult model.PageResult
+	var err error
+
+	fetch := func(pager model.PageInfo, options model.ListOptions) ([]model.AirportData, int, model.PageResult, error) {
+		return service.Repo.GetAitportItems(ctx, pager, options)
+	}
+
+	if query.Q != "" {
+		// Поиск ограничивает список найденными аэропортами
+		names, nameErr := service.Repo.GetAirportNames(ctx)
+		if nameErr != nil {
+			return model.ServiceListResult[model.AirportData]{}, repoError("GetAirportNames", nameErr)
+		}
+
+		ranks := util.RankSearch(query.Q, airportCandidates(names))
+		data, total, pageResult, err = searchList(ranks, pager, options, fetch,
+			func(p model.AirportData) string { return p.Code })
+	} else {
+		data, total, pageResult, err = fetch(pager, options)
+	}
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetAitportItems': %v", err)
-        return model.ServiceListResult[model.AirportData]{}, err
+        return model.ServiceListResult[model.AirportData]{}, repoError("GetAitportItems", err)
     }
 
-	result := model.ServiceListResult[model.AirportData] { Result: true, Total: total, Items: &data }
+	setAirportVersions(data)
+
+	result := model.ServiceListResult[model.AirportData] { Result: true, Total: total, Items: &data,
+		NextCursor: pageResult.NextCursor, PrevCursor: pageResult.PrevCursor, TotalMode: pageResult.TotalMode }
 
 	return result, nil
 }
 
-func (service AirportService) GetAirportByCode(code string) (model.ServiceDataResult[model.AirportData], error) {
+func (service AirportService) GetAirportByCode(ctx context.Context, code string) (model.ServiceDataResult[model.AirportData], error) {
 
-	data, err := service.Repo.GetAitportItemByCode(code)
+	data, err := service.Repo.GetAitportItemByCode(ctx, code)
     if err != nil {
-		log.Fatalf("Ошибка запроса данных 'GetAitportItemByCode': %v", err)
-        return model.ServiceDataResult[model.AirportData]{}, err
+        return model.ServiceDataResult[model.AirportData]{}, repoError("GetAitportItemByCode", err)
     }	
 
+    if (data == nil) {
+        return model.ServiceDataResult[model.AirportData]{}, domain.NewNotFoundError(i18n.MsgAirportNotFound, code)
+    }
+
+    data.Version = airportVersion(*data)
+
+	result := model.ServiceDataResult[model.AirportData] { Result: true, Data: data }
+
+	return result, nil
+}
+
+func (service AirportService) CreateAirport(ctx context.Context, input model.AirportInput) (model.ServiceDataResult[model.AirportData], error) {
+
+    if validations := util.ValidateInput(input); len(validations) > 0 {
+        return model.ServiceDataResult[model.AirportData]{}, domain.NewValidationError(i18n.MsgInputInvalid, validations...)
+    }
+
+    uow, err := service.Repo.UnitOfWork()
+    if err != nil {
+        return model.ServiceDataResult[model.AirportData]{}, repoError("UnitOfWork", err)
+    }
+
+    var data *model.AirportData
+    err = uow.Do(ctx, func(tx repo.IAirportRepo) error {
+        exists, err := tx.GetAitportExistsByCode(ctx, input.Code) 
+        if err != nil {
+            return err
+        }
+
+        if (exists) {
+            return domain.NewConflictError(i18n.MsgAirportExists, input.Code)
+        }
+
+        data, err = tx.CreateAirport(ctx, input)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[model.AirportData]{}, repoError("CreateAirport", err)
+    }
+
+    data.Version = airportVersion(*data)
+
+	result := model.ServiceDataResult[model.AirportData] { Result: true, Data: data }
+
+	return result, nil
+}
+
+// UpdateAirport изменяет аэропорт; непустое условие ifMatch сравнивается с версией аэропорта в той же транзакции
+func (service AirportService) UpdateAirport(ctx context.Context, input model.AirportInput, ifMatch string) (model.ServiceDataResult[model.AirportData], error) {
+
+    if validations := util.ValidateInput(input); len(validations) > 0 {
+        return model.ServiceDataResult[model.AirportData]{}, domain.NewValidationError(i18n.MsgInputInvalid, validations...)
+    }
+
+    uow, err := service.Repo.UnitOfWork()
+    if err != nil {
+        return model.ServiceDataResult[model.AirportData]{}, repoError("UnitOfWork", err)
+    }
+
+    var data *model.AirportData
+    err = uow.Do(ctx, func(tx repo.IAirportRepo) error {
+        exists, err := tx.GetAitportExistsByCode(ctx, input.Code) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgAirportNotFound, input.Code)
+        }
+
+        if err := checkAirportVersion(ctx, tx, input.Code, ifMatch); err != nil {
+            return err
+        }
+
+        data, err = tx.UpdateAirport(ctx, input)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[model.AirportData]{}, repoError("UpdateAirport", err)
+    }
+
+    data.Version = airportVersion(*data)
+
 	result := model.ServiceDataResult[model.AirportData] { Result: true, Data: data }
 
 	return result, nil
 }
 
+// DeleteAirport удаляет аэропорт без рейсов; непустое условие ifMatch сравнивается с версией аэропорта в той же транзакции
+func (service AirportService) DeleteAirport(ctx context.Context, code string, ifMatch string) (model.ServiceDataResult[string], error) {
+
+    uow, err := service.Repo.UnitOfWork()
+    if err != nil {
+        return model.ServiceDataResult[string]{}, repoError("UnitOfWork", err)
+    }
+
+    var data *string
+    err = uow.Do(ctx, func(tx repo.IAirportRepo) error {
+        exists, err := tx.GetAitportExistsByCode(ctx, code) 
+        if err != nil {
+            return err
+        }
+
+        if (!exists) {
+            return domain.NewNotFoundError(i18n.MsgAirportNotFound, code)
+        }
+
+        if err := checkAirportVersion(ctx, tx, code, ifMatch); err != nil {
+            return err
+        }
+
+        // Аэропорт, на который ссылаются рейсы, удалять нельзя
+        flights, err := tx.GetAirportFlightsCount(ctx, code) 
+        if err != nil {
+            return err
+        }
+
+        if (flights > 0) {
+            return domain.NewConflictError(i18n.MsgAirportInUse, code, flights)
+        }
+
+        data, err = tx.DeleteAirport(ctx, code)
+        return err
+    })
+    if err != nil {
+        return model.ServiceDataResult[string]{}, repoError("DeleteAirport", err)
+    }
+
+	result := model.ServiceDataResult[string] { Result: true, Data: data }
+
+	return result, nil
+}
+
+// AirportFeatures возвращает аэропорты как точки GeoJSON с названиями, городом и часовым поясом в свойствах
+func AirportFeatures(airports []model.AirportData) geo.FeatureCollection {
+	return geo.NewFeatureCollection(util.Map(airports, func(p model.AirportData) geo.Feature {
+		return geo.NewPointFeature(p.Code, geo.NewPoint(p.Latitude, p.Longitude), map[string]any{
+			"code":     p.Code,
+			"nameRu":   p.NameRu,
+			"nameEn":   p.NameEn,
+			"cityRu":   p.CityRu,
+			"cityEn":   p.CityEn,
+			"timezone": p.Timezone,
+		})
+	}))
+}
+
+// GetNearbyAirports возвращает аэропорты рядом с точкой по возрастанию расстояния:
+// ближайшие size, в радиусе radius км или в прямоугольнике bbox.
+// Для прямоугольника без точки расстояния считаются от его центра.
+func (service AirportService) GetNearbyAirports(ctx context.Context, input model.GeoInput) (model.ServiceListResult[model.AirportGeoData], error) {
+
+	center, box, validations := parseGeoInput(input)
+	if len(validations) > 0 {
+		return model.ServiceListResult[model.AirportGeoData]{},
+			domain.NewValidationError(i18n.MsgNearbyInvalid, validations...)
+	}
+
+	var airports []model.AirportData
+	var err error
+
+	if box != nil {
+		// Прямоугольник отбирает кандидатов в базе, радиус уточняется расстоянием
+		airports, err = service.Repo.GetAirportsInBox(ctx, *box)
+		if err != nil {
+			return model.ServiceListResult[model.AirportGeoData]{}, repoError("GetAirportsInBox", err)
+		}
+	} else {
+		airports, err = service.Repo.GetAirportNames(ctx)
+		if err != nil {
+			return model.ServiceListResult[model.AirportGeoData]{}, repoError("GetAirportNames", err)
+		}
+	}
+
+	data := util.Map(airports, func(p model.AirportData) model.AirportGeoData {
+		position := geo.NewPoint(p.Latitude, p.Longitude)
+		return model.AirportGeoData{ Code: p.Code, NameRu: p.NameRu, NameEn: p.NameEn,
+			CityRu: p.CityRu, CityEn: p.CityEn, Latitude: p.Latitude, Longitude: p.Longitude, Timezone: p.Timezone,
+			DistanceKm: geo.Distance(center, position), Bearing: geo.Bearing(center, position) }
+	})
+
+	if input.Radius != nil {
+		data = util.Filter(data, func(p model.AirportGeoData) bool {
+			return p.DistanceKm <= *input.Radius
+		})
+	}
+
+	slices.SortStableFunc(data, func(a model.AirportGeoData, b model.AirportGeoData) int {
+		if a.DistanceKm != b.DistanceKm {
+			if a.DistanceKm < b.DistanceKm {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.Code, b.Code)
+	})
+
+	total := len(data)
+
+	// Total больше числа элементов, если выдача неполная
+	size := nearbyDefaultSize
+	if box != nil {
+		size = nearbyMaxSize
+	}
+	if input.Limit != nil {
+		size = *input.Limit
+	}
+	data = data[:min(size, len(data))]
+
+	result := model.ServiceListResult[model.AirportGeoData] { Result: true, Total: total, Items: &data,
+		TotalMode: model.TotalExact }
+
+	return result, nil
+}
+
+// parseGeoInput проверяет точку, радиус, прямоугольник и размер выдачи.
+// Возвращает точку отсчета расстояний и прямоугольник отбора аэропортов (nil - все аэропорты).
+func parseGeoInput(input model.GeoInput) (domain.Point, *geo.BBox, []model.Validation) {
+	var validations []model.Validation
+	var center domain.Point
+	var box *geo.BBox
+
+	hasPoint := input.Latitude != nil || input.Longitude != nil
+	if hasPoint {
+		if input.Latitude == nil || input.Longitude == nil {
+			validations = append(validations, i18n.Validation("lat", i18n.MsgGeoPointPair))
+		} else {
+			center = geo.NewPoint(*input.Latitude, *input.Longitude)
+			if err := geo.Validate(center); err != nil {
+				validations = append(validations, i18n.ValidationOf("lat", err))
+			}
+		}
+	}
+
+	switch {
+	case input.Radius != nil && input.BBox != "":
+		validations = append(validations, i18n.Validation("radius", i18n.MsgGeoRadiusBBox))
+	case input.BBox != "":
+		parsed, err := geo.ParseBBox(input.BBox)
+		if err != nil {
+			validations = append(validations, i18n.ValidationOf("bbox", err))
+			break
+		}
+		box = &parsed
+		if !hasPoint {
+			center = parsed.Center()
+		}
+	case !hasPoint:
+		validations = append(validations, i18n.Validation("lat", i18n.MsgGeoPointMissing))
+	case input.Radius != nil:
+		if *input.Radius <= 0 {
+			validations = append(validations, i18n.Validation("radius", i18n.MsgGeoRadius))
+			break
+		}
+		bounds := geo.BoundingBox(center, *input.Radius)
+		box = &bounds
+	}
+
+	if input.Limit != nil && (*input.Limit <= 0 || *input.Limit > nearbyMaxSize) {
+		validations = append(validations, i18n.Validation("size", i18n.MsgSearchSize, 1, nearbyMaxSize))
+	}
+
+	return center, box, validations
+}
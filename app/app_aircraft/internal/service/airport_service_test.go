@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/geo"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+// Репозиторий аэропортов с заданными ответами
+type airportRepoStub struct {
+	repo.IAirportRepo
+	airport *model.AirportData
+	exists  bool
+	flights int64
+	err     error
+	write   error
+}
+
+// Единица работы, вызывающая действия с заданным репозиторием или подключением без транзакции
+type unitOfWorkStub[T any] struct {
+	tx T
+}
+
+func (uow unitOfWorkStub[T]) Do(ctx context.Context, fn func(tx T) error) error {
+	return fn(uow.tx)
+}
+
+func (stub airportRepoStub) UnitOfWork() (repo.IUnitOfWork[repo.IAirportRepo], error) {
+	return unitOfWorkStub[repo.IAirportRepo]{tx: stub}, nil
+}
+
+func (stub airportRepoStub) CreateAirport(ctx context.Context, input model.AirportInput) (*model.AirportData, error) {
+	return stub.airport, stub.write
+}
+
+func (stub airportRepoStub) GetAirportForUpdate(ctx context.Context, code string) (*model.AirportData, error) {
+	return stub.airport, stub.err
+}
+
+func (stub airportRepoStub) DeleteAirport(ctx context.Context, code string) (*string, error) {
+	return &code, stub.write
+}
+
+func (stub airportRepoStub) GetAitportItemByCode(ctx context.Context, code string) (*model.AirportData, error) {
+	return stub.airport, stub.err
+}
+
+func (stub airportRepoStub) GetAitportExistsByCode(ctx context.Context, code string) (bool, error) {
+	return stub.exists, stub.err
+}
+
+func (stub airportRepoStub) GetAirportFlightsCount(ctx context.Context, code string) (int64, error) {
+	return stub.flights, stub.err
+}
+
+// TestService_AirportErrors тестирует типизированные ошибки сервиса аэропортов
+func TestService_AirportErrors(t *testing.T) {
+	// Настоящая ошибка подключения: порт 1 на локальном адресе закрыт
+	_, refused := pgconn.Connect(context.Background(), "postgres://test@127.0.0.1:1/test?connect_timeout=5")
+	var connectErr *pgconn.ConnectError
+	if !errors.As(refused, &connectErr) {
+		t.Fatalf("Ожидалась ошибка подключения, получено %v", refused)
+	}
+	connErr := fmt.Errorf("ошибка подключения: %w", refused)
+	uniqueErr := fmt.Errorf("ошибка сканирования строки: %w", &pgconn.PgError{Code: "23505", ConstraintName: "airports_data_pkey"})
+	foreignKeyErr := &pgconn.PgError{Code: "23503", ConstraintName: "flights_departure_airport_fkey"}
+	airport := model.AirportInput{Code: "CNN", NameRu: "Чульман", NameEn: "Chulman Airport",
+		CityRu: "Нерюнгри", CityEn: "Neryungri", Latitude: 56.913, Longitude: 124.914, Timezone: "Asia/Yakutsk"}
+
+	tests := []struct {
+		name     string
+		stub     airportRepoStub
+		call     func(service IAirportService) error
+		expected domain.ErrorKind
+	}{
+		{"GetAirportByCode_NotFound", airportRepoStub{},
+			func(service IAirportService) error { _, err := service.GetAirportByCode(context.Background(), "XXX"); return err },
+			domain.ErrorNotFound},
+		{"GetAirportByCode_Unavailable", airportRepoStub{err: connErr},
+			func(service IAirportService) error { _, err := service.GetAirportByCode(context.Background(), "CNN"); return err },
+			domain.ErrorUnavailable},
+		{"GetAirportByCode_Timeout", airportRepoStub{err: fmt.Errorf("ошибка запроса: %w", context.DeadlineExceeded)},
+			func(service IAirportService) error { _, err := service.GetAirportByCode(context.Background(), "CNN"); return err },
+			domain.ErrorTimeout},
+		{"GetAirportByCode_Internal", airportRepoStub{err: fmt.Errorf("syntax error")},
+			func(service IAirportService) error { _, err := service.GetAirportByCode(context.Background(), "CNN"); return err },
+			domain.ErrorInternal},
+		{"CreateAirport_Conflict", airportRepoStub{exists: true},
+			func(service IAirportService) error { _, err := service.CreateAirport(context.Background(), airport); return err },
+			domain.ErrorConflict},
+		{"DeleteAirport_HasFlights", airportRepoStub{exists: true, flights: 5},
+			func(service IAirportService) error { _, err := service.DeleteAirport(context.Background(), "CNN", ""); return err },
+			domain.ErrorConflict},
+		{"CreateAirport_UniqueViolation", airportRepoStub{write: uniqueErr},
+			func(service IAirportService) error { _, err := service.CreateAirport(context.Background(), airport); return err },
+			domain.ErrorConflict},
+		{"DeleteAirport_ForeignKeyViolation", airportRepoStub{exists: true, write: foreignKeyErr},
+			func(service IAirportService) error { _, err := service.DeleteAirport(context.Background(), "CNN", ""); return err },
+			domain.ErrorReference},
+		{"DeleteAirport_VersionMismatch", airportRepoStub{exists: true, airport: &model.AirportData{Code: "CNN"}},
+			func(service IAirportService) error { _, err := service.DeleteAirport(context.Background(), "CNN", `"0000"`); return err },
+			domain.ErrorPrecondition},
+		{"GetAirports_UnknownSortField", airportRepoStub{},
+			func(service IAirportService) error {
+				_, err := service.GetAirports(context.Background(), model.PageInfo{}, model.ListQuery{Sort: "-range"})
+				return err
+			},
+			domain.ErrorValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			err := tt.call(AirportService{Repo: tt.stub})
+
+			if err == nil {
+				t.Fatalf("Ожидалась ошибка вида '%v'", tt.expected)
+			}
+
+			if kind := domain.ErrorKindOf(err); kind != tt.expected {
+				t.Errorf("Ожидалась ошибка вида '%v', получена '%v': %v", tt.expected, kind, err)
+			}
+		})
+	}
+}
+
+// Репозиторий аэропортов с координатами, прямоугольник отбирается в памяти
+type airportGeoStub struct {
+	repo.IAirportRepo
+	airports []model.AirportData
+}
+
+func (stub airportGeoStub) GetAirportNames(ctx context.Context) ([]model.AirportData, error) {
+	return stub.airports, nil
+}
+
+func (stub airportGeoStub) GetAirportsInBox(ctx context.Context, box geo.BBox) ([]model.AirportData, error) {
+	return util.Filter(stub.airports, func(p model.AirportData) bool {
+		return box.Contains(geo.NewPoint(p.Latitude, p.Longitude))
+	}), nil
+}
+
+// TestService_GetNearbyAirports тестирует поиск аэропортов рядом с точкой
+func TestService_GetNearbyAirports(t *testing.T) {
+	service := AirportService{Repo: airportGeoStub{airports: []model.AirportData{
+		{Code: "DME", Latitude: 55.408798, Longitude: 37.906300},
+		{Code: "LED", Latitude: 59.800301, Longitude: 30.262501},
+		{Code: "SVO", Latitude: 55.972599, Longitude: 37.414600},
+		{Code: "VKO", Latitude: 55.591499, Longitude: 37.261501},
+		{Code: "DYR", Latitude: 64.734901, Longitude: 177.740997},
+	}}}
+
+	lat, lon := 55.751244, 37.618423 // центр Москвы
+	size, radius := 2, 100.0
+
+	tests := []struct {
+		name  string
+		input model.GeoInput
+		codes []string
+		total int
+	}{
+		{"Nearby_Size", model.GeoInput{Latitude: &lat, Longitude: &lon, Limit: &size}, []string{"SVO", "VKO"}, 5},
+		{"Nearby_Radius", model.GeoInput{Latitude: &lat, Longitude: &lon, Radius: &radius}, []string{"SVO", "VKO", "DME"}, 3},
+		{"Nearby_BBox", model.GeoInput{BBox: "170,60,-170,70"}, []string{"DYR"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			result, err := service.GetNearbyAirports(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Ошибка запроса данных 'GetNearbyAirports': %v", err)
+			}
+
+			var codes []string
+			for _, item := range *result.Items {
+				codes = append(codes, item.Code)
+			}
+
+			if !reflect.DeepEqual(codes, tt.codes) || result.Total != tt.total {
+				t.Errorf("Ожидались %v из %d, получено %v из %d", tt.codes, tt.total, codes, result.Total)
+			}
+		})
+	}
+
+	// Размер по умолчанию ограничивает только ближайшие аэропорты, отбор по области выдается полностью
+	var many []model.AirportData
+	for i := 0; i < nearbyDefaultSize+2; i++ {
+		many = append(many, model.AirportData{Code: fmt.Sprintf("A%02d", i), Latitude: lat + float64(i)/100, Longitude: lon})
+	}
+	area := AirportService{Repo: airportGeoStub{airports: many}}
+
+	for _, input := range []model.GeoInput{
+		{Latitude: &lat, Longitude: &lon},
+		{Latitude: &lat, Longitude: &lon, Radius: &radius},
+		{BBox: "37,55,38,57"},
+	} {
+		result, err := area.GetNearbyAirports(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Ошибка запроса данных 'GetNearbyAirports': %v", err)
+		}
+
+		expected := len(many)
+		if input.Radius == nil && input.BBox == "" {
+			expected = nearbyDefaultSize
+		}
+		if len(*result.Items) != expected || result.Total != len(many) {
+			t.Errorf("Для %+v ожидалось %d из %d, получено %d из %d", input, expected, len(many), len(*result.Items), result.Total)
+		}
+	}
+
+	_, err := service.GetNearbyAirports(context.Background(), model.GeoInput{Latitude: &lat, Radius: &radius})
+	if kind := domain.ErrorKindOf(err); kind != domain.ErrorValidation {
+		t.Errorf("Ожидалась ошибка валидации без долготы, получено %v", err)
+	}
+}
+
+// TestAirportFeatures тестирует представление аэропортов точками GeoJSON
+func TestAirportFeatures(t *testing.T) {
+	collection := AirportFeatures([]model.AirportData{
+		{Code: "SVO", NameRu: "Шереметьево", CityRu: "Москва", Latitude: 55.972599, Longitude: 37.414600, Timezone: "Europe/Moscow"},
+	})
+
+	data, err := json.Marshal(collection)
+	if err != nil {
+		t.Fatalf("Ошибка сериализации GeoJSON: %v", err)
+	}
+
+	expected := `{"type":"FeatureCollection","features":[{"type":"Feature","id":"SVO",` +
+		`"geometry":{"type":"Point","coordinates":[37.4146,55.972599]},` +
+		`"properties":{"cityEn":"","cityRu":"Москва","code":"SVO","nameEn":"","nameRu":"Шереметьево","timezone":"Europe/Moscow"}}]}`
+	if string(data) != expected {
+		t.Errorf("Ожидалось %s, получено %s", expected, data)
+	}
+
+	if data, _ := json.Marshal(AirportFeatures(nil)); string(data) != `{"type":"FeatureCollection","features":[]}` {
+		t.Errorf("Ожидалась пустая коллекция, получено %s", data)
+	}
+}
+
+// TestService_AirportInputValidation тестирует проверку входных данных аэропорта до обращения к репозиторию:
+// сервис без репозитория возвращает ошибку валидации, а не панику
+func TestService_AirportInputValidation(t *testing.T) {
+	_, err := AirportService{}.CreateAirport(context.Background(), model.AirportInput{Code: "SVO", Timezone: "Mars/Olympus"})
+
+	var serr *domain.ServiceError
+	if !errors.As(err, &serr) || serr.Kind != domain.ErrorValidation || len(serr.Validations) == 0 {
+		t.Fatalf("Ожидалась ошибка валидации, получено %v", err)
+	}
+	for _, validation := range serr.Validations {
+		if validation.Property == "" {
+			t.Errorf("Не задано свойство для '%s'", validation.Message)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+)
+
+// Коды SQLSTATE нарушений ограничений PostgreSQL
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// repoError записывает ошибку репозитория в журнал и приводит ее к типизированной ошибке сервиса
+func repoError(operation string, err error) error {
+
+	log.Printf("Ошибка запроса данных '%s': %v", operation, err)
+
+	var serr *domain.ServiceError
+	if errors.As(err, &serr) {
+		return serr
+	}
+
+	// Нарушения ограничений, которые обнаружила база данных при конкурентной записи
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return domain.NewConflictError(i18n.MsgErrorUnique, pgErr.ConstraintName)
+		case pgForeignKeyViolation:
+			return domain.NewReferenceError(i18n.MsgErrorReference, pgErr.ConstraintName)
+		}
+	}
+
+	if isTimeoutError(err) {
+		return domain.NewTimeoutError(i18n.MsgErrorTimeout, err)
+	}
+
+	if isUnavailableError(err) {
+		return domain.NewUnavailableError(i18n.MsgErrorUnavailable, err)
+	}
+
+	return domain.NewInternalError(i18n.MsgErrorQuery, err, operation)
+}
+
+// isTimeoutError определяет ошибки превышения времени выполнения запроса
+func isTimeoutError(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || pgconn.Timeout(err)
+}
+
+// isUnavailableError определяет ошибки подключения к базе данных
+func isUnavailableError(err error) bool {
+	var connErr *pgconn.ConnectError
+	var netErr net.Error
+
+	return errors.As(err, &connErr) || errors.As(err, &netErr) || errors.Is(err, driver.ErrBadConn)
+}
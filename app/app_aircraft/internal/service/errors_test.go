@@ -0,0 +1,49 @@
+package service
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+)
+
+// TestRepoError_Constraints тестирует, что нарушения ограничений возвращаются ключом сообщения и именем
+// ограничения без текста ошибки драйвера
+func TestRepoError_Constraints(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		kind       domain.ErrorKind
+		key        string
+		constraint string
+	}{
+		{"Unique", &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "aircrafts_pkey",
+			Message: `duplicate key value violates unique constraint "aircrafts_pkey"`, TableName: "aircrafts_data"},
+			domain.ErrorConflict, i18n.MsgErrorUnique, "aircrafts_pkey"},
+		{"ForeignKey", fmt.Errorf("ошибка выполнения запроса DeleteAirport: %w", &pgconn.PgError{Code: pgForeignKeyViolation,
+			ConstraintName: "flights_departure_airport_fkey", TableName: "flights",
+			Message: `update or delete on table "airports_data" violates foreign key constraint`}),
+			domain.ErrorReference, i18n.MsgErrorReference, "flights_departure_airport_fkey"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serr := domain.AsServiceError(repoError(tt.name, tt.err))
+
+			if serr.Kind != tt.kind || serr.Key != tt.key {
+				t.Fatalf("Ожидалась ошибка '%v' с ключом '%s', получено '%v' с ключом '%s'", tt.kind, tt.key, serr.Kind, serr.Key)
+			}
+
+			if !reflect.DeepEqual(serr.Args, []any{tt.constraint}) {
+				t.Errorf("Ожидалось имя ограничения '%s', получено %v", tt.constraint, serr.Args)
+			}
+
+			if serr.Err != nil {
+				t.Errorf("Ошибка драйвера передается в ответ: %v", serr.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+    "context"
+
+    "github.com/snpavlov/app_aircraft/internal/conf"
+    "github.com/snpavlov/app_aircraft/internal/domain"
+    "github.com/snpavlov/app_aircraft/internal/i18n"
+    "github.com/snpavlov/app_aircraft/internal/geo"
+    "github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+
+// Определяем интерфейс сервиса IFlightService
+type IFlightService interface {
+	GetFlights(ctx context.Context, filter model.FlightFilter, pager model.PageInfo) (model.ServiceListResult[model.FlightData], error)
+	GetFlightById(ctx context.Context, id int64) (model.ServiceDataResult[model.FlightData], error)
+	GetRoutes(ctx context.Context, filter model.RouteFilter) (model.ServiceListResult[model.RouteData], error)
+}
+
+type FlightService struct {
+    Repo repo.IFlightRepo
+}
+
+func (service FlightService) NewFlightService(config conf.IConfiguration) (IFlightService, error) {
+
+    // создать экземпляр репозитория с общим пулом соединений
+    gormContext, err := repo.GormDBContext{}.NewGormDBContext(config)
+    if err != nil {
+        return nil, err
+    }
+
+    service.Repo = gormContext
+
+	return service, nil
+}
+
+func (service FlightService) GetFlights(ctx context.Context, filter model.FlightFilter, pager model.PageInfo) (model.ServiceListResult[model.FlightData], error) {
+
+	data, total, pageResult, err := service.Repo.GetFlightItems(ctx, filter, pager)
+    if err != nil {
+        return model.ServiceListResult[model.FlightData]{}, repoError("GetFlightItems", err)
+    }
+
+	result := model.ServiceListResult[model.FlightData] { Result: true, Total: total, Items: &data,
+		NextCursor: pageResult.NextCursor, PrevCursor: pageResult.PrevCursor, TotalMode: pageResult.TotalMode }
+
+	return result, nil
+}
+
+func (service FlightService) GetFlightById(ctx context.Context, id int64) (model.ServiceDataResult[model.FlightData], error) {
+
+	data, err := service.Repo.GetFlightItemById(ctx, id)
+    if err != nil {
+        return model.ServiceDataResult[model.FlightData]{}, repoError("GetFlightItemById", err)
+    }
+
+    if (data == nil) {
+        return model.ServiceDataResult[model.FlightData]{}, domain.NewNotFoundError(i18n.MsgFlightNotFound, id)
+    }
+
+	result := model.ServiceDataResult[model.FlightData] { Result: true, Data: data }
+
+	return result, nil
+}
+
+func (service FlightService) GetRoutes(ctx context.Context, filter model.RouteFilter) (model.ServiceListResult[model.RouteData], error) {
+
+	data, err := service.Repo.GetRouteItems(ctx, filter)
+    if err != nil {
+        return model.ServiceListResult[model.RouteData]{}, repoError("GetRouteItems", err)
+    }
+
+	result := model.ServiceListResult[model.RouteData] { Result: true, Total: len(data), Items: &data,
+		TotalMode: model.TotalExact }
+
+	return result, nil
+}
+
+// RouteFeatures возвращает маршруты как линии GeoJSON из аэропорта вылета в аэропорт прилета
+func RouteFeatures(routes []model.RouteData) geo.FeatureCollection {
+	return geo.NewFeatureCollection(util.Map(routes, func(p model.RouteData) geo.Feature {
+		return geo.NewLineFeature(p.DepartureCode+"-"+p.ArrivalCode,
+			geo.NewPoint(p.DepartureLatitude, p.DepartureLongitude),
+			geo.NewPoint(p.ArrivalLatitude, p.ArrivalLongitude),
+			map[string]any{
+				"departure":  p.DepartureCode,
+				"arrival":    p.ArrivalCode,
+				"aircraft":   p.AircraftCodes,
+				"flights":    p.Flights,
+				"distanceKm": p.DistanceKm,
+			})
+	}))
+}
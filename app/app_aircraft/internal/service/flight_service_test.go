@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+)
+
+// Репозиторий рейсов с заданными маршрутами
+type routeRepoStub struct {
+	repo.IFlightRepo
+	routes []model.RouteData
+}
+
+func (stub routeRepoStub) GetRouteItems(ctx context.Context, filter model.RouteFilter) ([]model.RouteData, error) {
+	return stub.routes, nil
+}
+
+// TestService_GetRoutes тестирует представление маршрутов линиями GeoJSON
+func TestService_GetRoutes(t *testing.T) {
+	service := FlightService{Repo: routeRepoStub{routes: []model.RouteData{
+		{DepartureCode: "SVO", ArrivalCode: "LED", DepartureLatitude: 55.972599, DepartureLongitude: 37.414600,
+			ArrivalLatitude: 59.800301, ArrivalLongitude: 30.262501, AircraftCodes: []string{"321", "SU9"}, Flights: 12},
+	}}}
+
+	result, err := service.GetRoutes(context.Background(), model.RouteFilter{})
+	if err != nil {
+		t.Fatalf("Ошибка запроса данных 'GetRoutes': %v", err)
+	}
+
+	collection := RouteFeatures(*result.Items)
+	if len(collection.Features) != 1 || result.Total != 1 {
+		t.Fatalf("Ожидался один маршрут, получено %+v", collection)
+	}
+
+	feature := collection.Features[0]
+	if feature.Id != "SVO-LED" || feature.Geometry.Type != "LineString" || feature.Properties["flights"] != 12 {
+		t.Errorf("Неверный объект маршрута %+v", feature)
+	}
+}
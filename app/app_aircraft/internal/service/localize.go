@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+// LocalizeAircraft возвращает самолет с названием на первом доступном языке цепочки chain
+func LocalizeAircraft(item model.AircraftData, chain []string) model.LocalizedAircraftData {
+	name, lang := util.Localize(names(item.Names, item.NameRu, item.NameEn), chain)
+
+	return model.LocalizedAircraftData{Code: item.Code, Name: name, Lang: lang,
+		Range: item.Range, SeatCount: item.SeatCount, Seats: item.Seats, Version: item.Version}
+}
+
+// LocalizeAirport возвращает аэропорт с названием и городом на первом доступном языке цепочки chain.
+// Язык названия определяет Lang, город выбирается по той же цепочке отдельно.
+func LocalizeAirport(item model.AirportData, chain []string) model.LocalizedAirportData {
+	name, lang := util.Localize(names(item.Names, item.NameRu, item.NameEn), chain)
+	city, _ := util.Localize(names(item.CityNames, item.CityRu, item.CityEn), chain)
+
+	return model.LocalizedAirportData{Code: item.Code, Name: name, City: city, Lang: lang,
+		Latitude: item.Latitude, Longitude: item.Longitude, Timezone: item.Timezone, Version: item.Version,
+		LastDepartures: item.LastDepartures, LastArrivals: item.LastArrivals}
+}
+
+// LocalizeList применяет localize к элементам результата списка, сохраняя общее количество и курсоры
+func LocalizeList[T any, L any](result model.ServiceListResult[T], localize func(T) L) model.ServiceListResult[L] {
+	localized := model.ServiceListResult[L]{Result: result.Result, Message: result.Message,
+		Validations: result.Validations, Code: result.Code, Total: result.Total,
+		NextCursor: result.NextCursor, PrevCursor: result.PrevCursor, TotalMode: result.TotalMode}
+
+	if result.Items != nil {
+		items := util.Map(*result.Items, localize)
+		localized.Items = &items
+	}
+
+	return localized
+}
+
+// LocalizeData применяет localize к данным результата
+func LocalizeData[T any, L any](result model.ServiceDataResult[T], localize func(T) L) model.ServiceDataResult[L] {
+	localized := model.ServiceDataResult[L]{Result: result.Result, Message: result.Message,
+		Validations: result.Validations, Code: result.Code}
+
+	if result.Data != nil {
+		data := localize(*result.Data)
+		localized.Data = &data
+	}
+
+	return localized
+}
+
+// names возвращает названия на всех языках, русское и английское названия из отдельных полей
+func names(values map[string]string, ru string, en string) map[string]string {
+	all := make(map[string]string, len(values)+2)
+	for lang, value := range values {
+		all[lang] = value
+	}
+	if ru != "" {
+		all["ru"] = ru
+	}
+	if en != "" {
+		all["en"] = en
+	}
+	return all
+}
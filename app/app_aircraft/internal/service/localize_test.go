@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestLocalizeAirport тестирует выбор названия и города по цепочке языков
+func TestLocalizeAirport(t *testing.T) {
+	airport := model.AirportData{Code: "SVO", NameRu: "Шереметьево", NameEn: "Sheremetyevo International Airport",
+		CityRu: "Москва", CityEn: "Moscow",
+		Names:     map[string]string{"ru": "Шереметьево", "en": "Sheremetyevo International Airport", "de": "Flughafen Scheremetjewo"},
+		CityNames: map[string]string{"ru": "Москва", "en": "Moscow"}}
+
+	tests := []struct {
+		name  string
+		chain []string
+		value string
+		city  string
+		lang  string
+	}{
+		{"Localize_German", []string{"de", "en", "ru"}, "Flughafen Scheremetjewo", "Moscow", "de"},
+		{"Localize_Russian", []string{"ru", "en"}, "Шереметьево", "Москва", "ru"},
+		{"Localize_Fallback", []string{"fr", "en", "ru"}, "Sheremetyevo International Airport", "Moscow", "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := LocalizeAirport(airport, tt.chain)
+			if item.Name != tt.value || item.City != tt.city || item.Lang != tt.lang || item.Code != "SVO" {
+				t.Errorf("Ожидалось %s/%s (%s), получено %+v", tt.value, tt.city, tt.lang, item)
+			}
+		})
+	}
+}
+
+// TestLocalizeList тестирует сохранение общего количества и курсоров списка
+func TestLocalizeList(t *testing.T) {
+	cursor := "next"
+	items := []model.AircraftData{{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300"}}
+	result := model.ServiceListResult[model.AircraftData]{Result: true, Total: 5, Items: &items,
+		NextCursor: &cursor, TotalMode: model.TotalExact}
+
+	localized := LocalizeList(result, func(p model.AircraftData) model.LocalizedAircraftData {
+		return LocalizeAircraft(p, []string{"ru"})
+	})
+
+	if localized.Total != 5 || localized.NextCursor != &cursor || localized.TotalMode != model.TotalExact {
+		t.Errorf("Не сохранены параметры списка: %+v", localized)
+	}
+	if (*localized.Items)[0].Name != "Боинг 777-300" {
+		t.Errorf("Ожидалось русское название, получено %+v", *localized.Items)
+	}
+}
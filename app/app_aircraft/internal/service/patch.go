@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+// PatchAircraft изменяет только поля самолета из документа JSON Merge Patch, включая отдельные языки названий.
+// Непустое условие ifMatch сравнивается с версией самолета в той же транзакции.
+func (service AircraftService) PatchAircraft(ctx context.Context, code string, patch []byte, ifMatch string) (model.ServiceDataResult[model.AircraftData], error) {
+
+	uow, err := service.Repo.UnitOfWork()
+	if err != nil {
+		return model.ServiceDataResult[model.AircraftData]{}, repoError("UnitOfWork", err)
+	}
+
+	var data *model.AircraftData
+	err = uow.Do(ctx, func(tx repo.DBTX) error {
+		current, err := service.Repo.GetAircraftForUpdate(ctx, tx, code)
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			return domain.NewNotFoundError(i18n.MsgAircraftNotFound, code)
+		}
+
+		if ifMatch != "" {
+			if err := checkVersion(ifMatch, code, aircraftVersion(*current)); err != nil {
+				return err
+			}
+		}
+
+		input, err := patchAircraftInput(*current, patch)
+		if err != nil {
+			return err
+		}
+
+		if validations := util.ValidateInput(input); len(validations) > 0 {
+			return domain.NewValidationError(i18n.MsgInputInvalid, validations...)
+		}
+
+		data, err = service.Repo.ReplaceAircraft(ctx, tx, input)
+		return err
+	})
+	if err != nil {
+		return model.ServiceDataResult[model.AircraftData]{}, repoError("PatchAircraft", err)
+	}
+
+	data.Version = aircraftVersion(*data)
+
+	result := model.ServiceDataResult[model.AircraftData]{Result: true, Data: data}
+
+	return result, nil
+}
+
+// PatchAirport изменяет только поля аэропорта из документа JSON Merge Patch, включая отдельные языки названий и города.
+// Непустое условие ifMatch сравнивается с версией аэропорта в той же транзакции.
+func (service AirportService) PatchAirport(ctx context.Context, code string, patch []byte, ifMatch string) (model.ServiceDataResult[model.AirportData], error) {
+
+	uow, err := service.Repo.UnitOfWork()
+	if err != nil {
+		return model.ServiceDataResult[model.AirportData]{}, repoError("UnitOfWork", err)
+	}
+
+	var data *model.AirportData
+	err = uow.Do(ctx, func(tx repo.IAirportRepo) error {
+		current, err := tx.GetAirportForUpdate(ctx, code)
+		if err != nil {
+			return err
+		}
+
+		if current == nil {
+			return domain.NewNotFoundError(i18n.MsgAirportNotFound, code)
+		}
+
+		if ifMatch != "" {
+			if err := checkVersion(ifMatch, code, airportVersion(*current)); err != nil {
+				return err
+			}
+		}
+
+		input, err := patchAirportInput(*current, patch)
+		if err != nil {
+			return err
+		}
+
+		if validations := util.ValidateInput(input); len(validations) > 0 {
+			return domain.NewValidationError(i18n.MsgInputInvalid, validations...)
+		}
+
+		data, err = tx.ReplaceAirport(ctx, input)
+		return err
+	})
+	if err != nil {
+		return model.ServiceDataResult[model.AirportData]{}, repoError("PatchAirport", err)
+	}
+
+	data.Version = airportVersion(*data)
+
+	result := model.ServiceDataResult[model.AirportData]{Result: true, Data: data}
+
+	return result, nil
+}
+
+// patchAircraftInput применяет документ изменений к входным данным текущего самолета
+func patchAircraftInput(current model.AircraftData, patch []byte) (model.AircraftInput, error) {
+	input := model.AircraftInput{Code: current.Code, NameRu: current.NameRu, NameEn: current.NameEn,
+		Names: names(current.Names, current.NameRu, current.NameEn), Range: current.Range}
+
+	patched, err := util.MergePatch(input, patch)
+	if err != nil {
+		return patched, domain.NewArgumentError(i18n.MsgRequestPatch, err)
+	}
+
+	if patched.Code != current.Code {
+		return patched, domain.NewArgumentError(i18n.MsgPatchCode, nil, patched.Code, current.Code)
+	}
+
+	patched.NameRu, patched.NameEn, patched.Names = patchedNames(input.NameRu, input.NameEn,
+		patched.NameRu, patched.NameEn, patched.Names)
+
+	return patched, nil
+}
+
+// patchAirportInput применяет документ изменений к входным данным текущего аэропорта
+func patchAirportInput(current model.AirportData, patch []byte) (model.AirportInput, error) {
+	input := model.AirportInput{Code: current.Code, NameRu: current.NameRu, NameEn: current.NameEn,
+		CityRu: current.CityRu, CityEn: current.CityEn,
+		Names:     names(current.Names, current.NameRu, current.NameEn),
+		CityNames: names(current.CityNames, current.CityRu, current.CityEn),
+		Latitude:  current.Latitude, Longitude: current.Longitude, Timezone: current.Timezone}
+
+	patched, err := util.MergePatch(input, patch)
+	if err != nil {
+		return patched, domain.NewArgumentError(i18n.MsgRequestPatch, err)
+	}
+
+	if patched.Code != current.Code {
+		return patched, domain.NewArgumentError(i18n.MsgPatchCode, nil, patched.Code, current.Code)
+	}
+
+	patched.NameRu, patched.NameEn, patched.Names = patchedNames(input.NameRu, input.NameEn,
+		patched.NameRu, patched.NameEn, patched.Names)
+	patched.CityRu, patched.CityEn, patched.CityNames = patchedNames(input.CityRu, input.CityEn,
+		patched.CityRu, patched.CityEn, patched.CityNames)
+
+	return patched, nil
+}
+
+// patchedNames согласует русское и английское названия после изменений: они передаются и отдельными полями,
+// и ключами ru, en карты названий. Изменение поля важнее ключа, иначе берется значение ключа,
+// так что удаление ключа удаляет и название. Возвращает названия и карту с согласованными ключами.
+func patchedNames(ru string, en string, patchedRu string, patchedEn string, values map[string]string) (string, string, map[string]string) {
+	if patchedRu == ru {
+		patchedRu = values["ru"]
+	}
+	if patchedEn == en {
+		patchedEn = values["en"]
+	}
+
+	all := names(values, patchedRu, patchedEn)
+	if patchedRu == "" {
+		delete(all, "ru")
+	}
+	if patchedEn == "" {
+		delete(all, "en")
+	}
+
+	return patchedRu, patchedEn, all
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestPatchAircraftInput тестирует применение документа изменений к текущему самолету
+func TestPatchAircraftInput(t *testing.T) {
+	current := model.AircraftData{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+		Names: map[string]string{"ru": "Боинг 777-300", "en": "Boeing 777-300", "de": "Boeing 777-300", "fr": "Boeing 777-300"},
+		Range: 11100, SeatCount: 402}
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected *model.AircraftInput
+		kind     domain.ErrorKind
+	}{
+		{"Range", `{"range": 11200}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+				Names: map[string]string{"ru": "Боинг 777-300", "en": "Boeing 777-300", "de": "Boeing 777-300", "fr": "Boeing 777-300"},
+				Range: 11200}, 0},
+		{"NameKeys", `{"names": {"de": "Boeing 777-300 (DE)", "fr": null}}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+				Names: map[string]string{"ru": "Боинг 777-300", "en": "Boeing 777-300", "de": "Boeing 777-300 (DE)"},
+				Range: 11100}, 0},
+		{"NameField", `{"nameEn": "Boeing 777-300 (773)"}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300 (773)",
+				Names: map[string]string{"ru": "Боинг 777-300", "en": "Boeing 777-300 (773)", "de": "Boeing 777-300", "fr": "Boeing 777-300"},
+				Range: 11100}, 0},
+		{"NameKeyRu", `{"names": {"ru": "Боинг 777-300 (773)"}}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300 (773)", NameEn: "Boeing 777-300",
+				Names: map[string]string{"ru": "Боинг 777-300 (773)", "en": "Boeing 777-300", "de": "Boeing 777-300", "fr": "Boeing 777-300"},
+				Range: 11100}, 0},
+		{"RemoveNameKeyEn", `{"names": {"en": null}}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300",
+				Names: map[string]string{"ru": "Боинг 777-300", "de": "Boeing 777-300", "fr": "Boeing 777-300"},
+				Range: 11100}, 0},
+		{"SameCode", `{"code": "773"}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+				Names: map[string]string{"ru": "Боинг 777-300", "en": "Boeing 777-300", "de": "Boeing 777-300", "fr": "Boeing 777-300"},
+				Range: 11100}, 0},
+		{"CodeMismatch", `{"code": "77W"}`, nil, domain.ErrorArgument},
+		{"UnknownField", `{"seatCount": 400}`, nil, domain.ErrorArgument},
+		{"Invalid", `range=11200`, nil, domain.ErrorArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := patchAircraftInput(current, []byte(tt.patch))
+
+			if tt.expected == nil {
+				if kind := domain.ErrorKindOf(err); err == nil || kind != tt.kind {
+					t.Fatalf("Ожидалась ошибка вида '%v', получено %v", tt.kind, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+
+			if !reflect.DeepEqual(input, *tt.expected) {
+				t.Errorf("Ожидалось %+v, получено %+v", *tt.expected, input)
+			}
+		})
+	}
+}
+
+// TestPatchAirportInput тестирует изменение отдельных языков названия и города аэропорта
+func TestPatchAirportInput(t *testing.T) {
+	current := model.AirportData{Code: "SVO", NameRu: "Шереметьево", NameEn: "Sheremetyevo International Airport",
+		CityRu: "Москва", CityEn: "Moscow", CityNames: map[string]string{"ru": "Москва", "en": "Moscow", "de": "Moskau"},
+		Latitude: 55.972599, Longitude: 37.414600, Timezone: "Europe/Moscow",
+		LastDepartures: &[]model.AirportFlightData{{Id: 1}}}
+
+	input, err := patchAirportInput(current, []byte(`{"cityNames": {"de": null, "fr": "Moscou"}, "cityEn": "Moskva", "latitude": 55.9736}`))
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	expected := model.AirportInput{Code: "SVO", NameRu: "Шереметьево", NameEn: "Sheremetyevo International Airport",
+		CityRu: "Москва", CityEn: "Moskva",
+		Names:     map[string]string{"ru": "Шереметьево", "en": "Sheremetyevo International Airport"},
+		CityNames: map[string]string{"ru": "Москва", "en": "Moskva", "fr": "Moscou"},
+		Latitude:  55.9736, Longitude: 37.414600, Timezone: "Europe/Moscow"}
+
+	if !reflect.DeepEqual(input, expected) {
+		t.Errorf("Ожидалось %+v, получено %+v", expected, input)
+	}
+}
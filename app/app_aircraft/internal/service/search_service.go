@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+// Типы результатов поиска
+const (
+	SearchAircraft = "aircraft"
+	SearchAirport  = "airport"
+)
+
+// Размер выдачи поиска по умолчанию и наибольший
+const (
+	searchDefaultSize = 10
+	searchMaxSize     = 50
+)
+
+// Определяем интерфейс сервиса поиска ISearchService
+type ISearchService interface {
+	Search(ctx context.Context, input model.SearchInput) (model.ServiceListResult[model.SearchItemData], error)
+}
+
+// SearchService ищет самолеты и аэропорты по кодам и названиям на русском и английском.
+// Справочники небольшие, поэтому ранжирование выполняется в памяти по всем записям.
+type SearchService struct {
+	AircraftRepo repo.IAircraftRepo
+	AirportRepo  repo.IAirportRepo
+}
+
+func (service SearchService) Search(ctx context.Context, input model.SearchInput) (model.ServiceListResult[model.SearchItemData], error) {
+
+	var validations []model.Validation
+	if strings.TrimSpace(input.Q) == "" {
+		validations = append(validations, i18n.Validation("q", i18n.MsgSearchQuery))
+	}
+	if input.Type != "" && input.Type != SearchAircraft && input.Type != SearchAirport {
+		validations = append(validations, i18n.Validation("type", i18n.MsgSearchType))
+	}
+	if input.Limit != nil && (*input.Limit <= 0 || *input.Limit > searchMaxSize) {
+		validations = append(validations, i18n.Validation("size", i18n.MsgSearchSize, 1, searchMaxSize))
+	}
+	if len(validations) > 0 {
+		return model.ServiceListResult[model.SearchItemData]{},
+			domain.NewValidationError(i18n.MsgSearchInvalid, validations...)
+	}
+
+	items := make(map[string]model.SearchItemData)
+	var candidates []util.SearchCandidate
+
+	if input.Type != SearchAirport {
+		db, err := service.AircraftRepo.GetDBConnection()
+		if err != nil {
+			return model.ServiceListResult[model.SearchItemData]{}, repoError("GetDBConnection", err)
+		}
+
+		aircrafts, err := service.AircraftRepo.GetAircraftNames(ctx, db)
+		if err != nil {
+			return model.ServiceListResult[model.SearchItemData]{}, repoError("GetAircraftNames", err)
+		}
+
+		for _, candidate := range aircraftCandidates(aircrafts) {
+			candidate.Key = SearchAircraft + ":" + candidate.Key
+			candidates = append(candidates, candidate)
+		}
+		for _, p := range aircrafts {
+			items[SearchAircraft+":"+p.Code] = model.SearchItemData{Type: SearchAircraft, Code: p.Code, NameRu: p.NameRu, NameEn: p.NameEn}
+		}
+	}
+
+	if input.Type != SearchAircraft {
+		airports, err := service.AirportRepo.GetAirportNames(ctx)
+		if err != nil {
+			return model.ServiceListResult[model.SearchItemData]{}, repoError("GetAirportNames", err)
+		}
+
+		for _, candidate := range airportCandidates(airports) {
+			candidate.Key = SearchAirport + ":" + candidate.Key
+			candidates = append(candidates, candidate)
+		}
+		for _, p := range airports {
+			items[SearchAirport+":"+p.Code] = model.SearchItemData{Type: SearchAirport, Code: p.Code,
+				NameRu: p.NameRu, NameEn: p.NameEn, CityRu: p.CityRu, CityEn: p.CityEn}
+		}
+	}
+
+	ranks := util.RankSearch(input.Q, candidates)
+	total := len(ranks)
+
+	size := searchDefaultSize
+	if input.Limit != nil {
+		size = *input.Limit
+	}
+	ranks = ranks[:min(size, len(ranks))]
+
+	data := util.Map(ranks, func(p util.SearchRank) model.SearchItemData {
+		item := items[p.Key]
+		item.Score = p.Score
+		item.Matches = p.Matches
+		return item
+	})
+
+	result := model.ServiceListResult[model.SearchItemData] { Result: true, Total: total, Items: &data,
+		TotalMode: model.TotalExact }
+
+	return result, nil
+}
+
+// aircraftCandidates возвращает поля самолетов, по которым выполняется поиск
+func aircraftCandidates(aircrafts []model.AircraftData) []util.SearchCandidate {
+	return util.Map(aircrafts, func(p model.AircraftData) util.SearchCandidate {
+		return util.SearchCandidate{Key: p.Code, Fields: []util.SearchField{
+			{Name: "code", Value: p.Code},
+			{Name: "nameRu", Value: p.NameRu},
+			{Name: "nameEn", Value: p.NameEn},
+		}}
+	})
+}
+
+// airportCandidates возвращает поля аэропортов, по которым выполняется поиск
+func airportCandidates(airports []model.AirportData) []util.SearchCandidate {
+	return util.Map(airports, func(p model.AirportData) util.SearchCandidate {
+		return util.SearchCandidate{Key: p.Code, Fields: []util.SearchField{
+			{Name: "code", Value: p.Code},
+			{Name: "nameRu", Value: p.NameRu},
+			{Name: "nameEn", Value: p.NameEn},
+			{Name: "cityRu", Value: p.CityRu},
+			{Name: "cityEn", Value: p.CityEn},
+		}}
+	})
+}
+
+// searchList читает список, ограниченный кодами, найденными поиском.
+// С явной сортировкой список читается обычным порядком страниц. Без сортировки
+// найденные записи читаются целиком, упорядочиваются по оценке поиска и делятся на страницы со смещением.
+func searchList[T any](ranks []util.SearchRank, pager model.PageInfo, options model.ListOptions,
+	fetch func(model.PageInfo, model.ListOptions) ([]T, int, model.PageResult, error),
+	keyFn func(T) string) ([]T, int, model.PageResult, error) {
+
+	options.Codes = util.Map(ranks, func(p util.SearchRank) string {
+		return p.Key
+	})
+
+	if len(options.Codes) == 0 {
+		mode := pager.TotalMode
+		if mode == "" {
+			mode = model.TotalExact
+		}
+		return []T{}, 0, model.PageResult{TotalMode: mode}, nil
+	}
+
+	if len(options.Order) > 0 {
+		return fetch(pager, options)
+	}
+
+	if pager.Cursor != nil && *pager.Cursor != "" {
+		return nil, 0, model.PageResult{}, domain.NewValidationError(i18n.MsgPageInvalid, i18n.Validation("cursor", i18n.MsgSearchCursor))
+	}
+
+	items, total, pageResult, err := fetch(model.PageInfo{TotalMode: pager.TotalMode}, options)
+	if err != nil {
+		return nil, 0, model.PageResult{}, err
+	}
+
+	position := make(map[string]int, len(ranks))
+	for i, p := range ranks {
+		position[p.Key] = i
+	}
+	slices.SortStableFunc(items, func(a T, b T) int {
+		return position[keyFn(a)] - position[keyFn(b)]
+	})
+
+	offset := 0
+	if pager.Offset != nil {
+		offset = min(max(*pager.Offset, 0), len(items))
+	}
+	items = items[offset:]
+	if pager.Limit != nil {
+		items = items[:min(max(*pager.Limit, 0), len(items))]
+	}
+
+	return items, total, model.PageResult{TotalMode: pageResult.TotalMode}, nil
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+)
+
+// Репозиторий самолетов с заданным справочником
+type aircraftNamesStub struct {
+	repo.IAircraftRepo
+	aircrafts []model.AircraftData
+}
+
+func (stub aircraftNamesStub) GetDBConnection() (*sql.DB, error) {
+	return nil, nil
+}
+
+func (stub aircraftNamesStub) GetAircraftNames(ctx context.Context, db *sql.DB) ([]model.AircraftData, error) {
+	return stub.aircrafts, nil
+}
+
+// Репозиторий аэропортов с заданным справочником, список возвращает записи с кодами фильтра поиска
+type airportNamesStub struct {
+	repo.IAirportRepo
+	airports []model.AirportData
+}
+
+func (stub airportNamesStub) GetAirportNames(ctx context.Context) ([]model.AirportData, error) {
+	return stub.airports, nil
+}
+
+func (stub airportNamesStub) GetAitportItems(ctx context.Context, pager model.PageInfo, options model.ListOptions) ([]model.AirportData, int, model.PageResult, error) {
+	var items []model.AirportData
+	for _, airport := range stub.airports {
+		for _, code := range options.Codes {
+			if airport.Code == code {
+				items = append(items, airport)
+			}
+		}
+	}
+	return items, len(items), model.PageResult{TotalMode: model.TotalExact}, nil
+}
+
+var searchAirports = []model.AirportData{
+	{Code: "DME", NameRu: "Домодедово", NameEn: "Domodedovo International Airport", CityRu: "Москва", CityEn: "Moscow"},
+	{Code: "KHV", NameRu: "Хабаровск-Новый", NameEn: "Khabarovsk-Novy Airport", CityRu: "Хабаровск", CityEn: "Khabarovsk"},
+	{Code: "SVO", NameRu: "Шереметьево", NameEn: "Sheremetyevo International Airport", CityRu: "Москва", CityEn: "Moscow"},
+}
+
+var searchAircrafts = []model.AircraftData{
+	{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300"},
+	{Code: "SU9", NameRu: "Сухой Суперджет-100", NameEn: "Sukhoi Superjet-100"},
+}
+
+// TestService_Search тестирует поиск по самолетам и аэропортам
+func TestService_Search(t *testing.T) {
+	service := SearchService{
+		AircraftRepo: aircraftNamesStub{aircrafts: searchAircrafts},
+		AirportRepo:  airportNamesStub{airports: searchAirports},
+	}
+
+	size := 1
+
+	tests := []struct {
+		name  string
+		input model.SearchInput
+		codes []string
+		total int
+	}{
+		{"Search_Translit", model.SearchInput{Q: "moskva"}, []string{"DME", "SVO"}, 2},
+		{"Search_Latin", model.SearchInput{Q: "boing"}, []string{"773"}, 1},
+		{"Search_Size", model.SearchInput{Q: "moskva", Limit: &size}, []string{"DME"}, 2},
+		{"Search_Type", model.SearchInput{Q: "su", Type: SearchAircraft}, []string{"SU9"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			result, err := service.Search(context.Background(), tt.input)
+			if err != nil {
+				t.Fatalf("Ошибка поиска: %v", err)
+			}
+
+			var codes []string
+			for _, item := range *result.Items {
+				codes = append(codes, item.Code)
+				if len(item.Matches) == 0 {
+					t.Errorf("Для '%s' не найдены совпадения для подсветки", item.Code)
+				}
+			}
+
+			if !reflect.DeepEqual(codes, tt.codes) || result.Total != tt.total {
+				t.Errorf("Ожидались %v из %d, получено %v из %d", tt.codes, tt.total, codes, result.Total)
+			}
+		})
+	}
+}
+
+// TestService_SearchValidation тестирует проверку параметров поиска
+func TestService_SearchValidation(t *testing.T) {
+	size := 100
+
+	tests := []struct {
+		name     string
+		input    model.SearchInput
+		property string
+	}{
+		{"Search_EmptyQuery", model.SearchInput{Q: " "}, "q"},
+		{"Search_UnknownType", model.SearchInput{Q: "svo", Type: "flight"}, "type"},
+		{"Search_Size", model.SearchInput{Q: "svo", Limit: &size}, "size"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			_, err := SearchService{}.Search(context.Background(), tt.input)
+
+			var serr *domain.ServiceError
+			if !errors.As(err, &serr) || serr.Kind != domain.ErrorValidation {
+				t.Fatalf("Ожидалась ошибка валидации, получено %v", err)
+			}
+
+			if serr.Validations[0].Property != tt.property {
+				t.Errorf("Ожидалась ошибка свойства '%s', получено %+v", tt.property, serr.Validations)
+			}
+		})
+	}
+}
+
+// TestService_GetAirportsSearch тестирует список аэропортов, упорядоченный по оценке поиска
+func TestService_GetAirportsSearch(t *testing.T) {
+	service := AirportService{Repo: airportNamesStub{airports: searchAirports}}
+
+	cursor := "x"
+
+	result, err := service.GetAirports(context.Background(), model.PageInfo{}, model.ListQuery{Q: "шереметьево"})
+	if err != nil {
+		t.Fatalf("Ошибка запроса данных 'GetAirports': %v", err)
+	}
+
+	if len(*result.Items) != 1 || (*result.Items)[0].Code != "SVO" || result.Total != 1 {
+		t.Errorf("Ожидался аэропорт SVO, получено %+v", *result.Items)
+	}
+
+	result, err = service.GetAirports(context.Background(), model.PageInfo{}, model.ListQuery{Q: "zzzz"})
+	if err != nil || len(*result.Items) != 0 {
+		t.Errorf("Ожидался пустой список, получено %+v, %v", result.Items, err)
+	}
+
+	_, err = service.GetAirports(context.Background(), model.PageInfo{Cursor: &cursor}, model.ListQuery{Q: "moskva"})
+	if kind := domain.ErrorKindOf(err); kind != domain.ErrorValidation {
+		t.Errorf("Ожидалась ошибка валидации курсора, получено %v", err)
+	}
+}
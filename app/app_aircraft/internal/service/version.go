@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/util"
+)
+
+// aircraftVersion возвращает версию самолета по полям, которые изменяет UpdateAircraft:
+// коду, названиям и дальности. Места изменяются отдельно и в версию не входят.
+func aircraftVersion(item model.AircraftData) string {
+	return util.Version(struct {
+		Code  string
+		Names map[string]string
+		Range int
+	}{item.Code, names(item.Names, item.NameRu, item.NameEn), item.Range})
+}
+
+// airportVersion возвращает версию аэропорта по полям, которые изменяет UpdateAirport.
+// Последние рейсы в версию не входят, чтобы версия в списке и в карточке аэропорта совпадала.
+func airportVersion(item model.AirportData) string {
+	return util.Version(struct {
+		Code      string
+		Names     map[string]string
+		CityNames map[string]string
+		Latitude  float64
+		Longitude float64
+		Timezone  string
+	}{item.Code, names(item.Names, item.NameRu, item.NameEn), names(item.CityNames, item.CityRu, item.CityEn),
+		item.Latitude, item.Longitude, item.Timezone})
+}
+
+// setAircraftVersions заполняет версии самолетов
+func setAircraftVersions(items []model.AircraftData) {
+	for i := range items {
+		items[i].Version = aircraftVersion(items[i])
+	}
+}
+
+// setAirportVersions заполняет версии аэропортов
+func setAirportVersions(items []model.AirportData) {
+	for i := range items {
+		items[i].Version = airportVersion(items[i])
+	}
+}
+
+// checkVersion сравнивает текущую версию объекта с условием If-Match: тегом ответа изменения
+// или тегом представления, полученным при чтении
+func checkVersion(ifMatch string, code string, version string) error {
+	if util.MatchVersion(ifMatch, version) {
+		return nil
+	}
+	return domain.NewPreconditionError(i18n.MsgVersionMismatch, code, util.ETag(version))
+}
+
+// checkAircraftVersion блокирует самолет в транзакции и проверяет его версию; пустое условие не проверяется
+func (service AircraftService) checkAircraftVersion(ctx context.Context, tx repo.DBTX, code string, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+
+	current, err := service.Repo.GetAircraftForUpdate(ctx, tx, code)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return domain.NewNotFoundError(i18n.MsgAircraftNotFound, code)
+	}
+
+	return checkVersion(ifMatch, code, aircraftVersion(*current))
+}
+
+// checkAirportVersion блокирует аэропорт в транзакции и проверяет его версию; пустое условие не проверяется
+func checkAirportVersion(ctx context.Context, tx repo.IAirportRepo, code string, ifMatch string) error {
+	if ifMatch == "" {
+		return nil
+	}
+
+	current, err := tx.GetAirportForUpdate(ctx, code)
+	if err != nil {
+		return err
+	}
+
+	if current == nil {
+		return domain.NewNotFoundError(i18n.MsgAirportNotFound, code)
+	}
+
+	return checkVersion(ifMatch, code, airportVersion(*current))
+}
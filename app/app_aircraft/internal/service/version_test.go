@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestVersion тестирует, что версия зависит только от изменяемых полей объекта
+func TestVersion(t *testing.T) {
+	aircraft := model.AircraftData{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300", Range: 11100}
+	airport := model.AirportData{Code: "SVO", NameRu: "Шереметьево", CityRu: "Москва", Latitude: 55.972599, Longitude: 37.414600, Timezone: "Europe/Moscow"}
+
+	tests := []struct {
+		name  string
+		first string
+		other string
+		equal bool
+	}{
+		{"Aircraft_Seats", aircraftVersion(aircraft),
+			aircraftVersion(func() model.AircraftData { p := aircraft; p.SeatCount = 402; return p }()), true},
+		{"Aircraft_Names", aircraftVersion(aircraft),
+			aircraftVersion(func() model.AircraftData {
+				p := aircraft
+				p.Names = map[string]string{"ru": p.NameRu, "en": p.NameEn}
+				return p
+			}()), true},
+		{"Aircraft_Range", aircraftVersion(aircraft),
+			aircraftVersion(func() model.AircraftData { p := aircraft; p.Range = 11200; return p }()), false},
+		{"Airport_Flights", airportVersion(airport),
+			airportVersion(func() model.AirportData { p := airport; p.LastDepartures = &[]model.AirportFlightData{{Id: 1}}; return p }()), true},
+		{"Airport_Timezone", airportVersion(airport),
+			airportVersion(func() model.AirportData { p := airport; p.Timezone = "Europe/Samara"; return p }()), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if (tt.first == tt.other) != tt.equal {
+				t.Errorf("Ожидалось совпадение версий %v, получено '%s' и '%s'", tt.equal, tt.first, tt.other)
+			}
+		})
+	}
+}
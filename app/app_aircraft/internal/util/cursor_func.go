@@ -0,0 +1,137 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Cursor - граница страницы: значения ключа сортировки первой или последней строки.
+// Клиент получает курсор как непрозрачную строку и возвращает его без изменений.
+type Cursor struct {
+	Sort string `json:"s"`           // сортировка, для которой выдан курсор
+	Keys []any  `json:"k"`           // значения полей сортировки граничной строки
+	Prev bool   `json:"p,omitempty"` // читать страницу перед границей
+}
+
+// EncodeCursor возвращает курсор в виде строки для URL
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor разбирает курсор, проверяет, что он выдан для той же сортировки,
+// и приводит значения ключа к типам полей сортировки
+func DecodeCursor(token string, order []model.OrderInfo, columns ListFields) (Cursor, error) {
+	var cursor Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return cursor, i18n.NewError(i18n.MsgCursorInvalid)
+	}
+
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return cursor, i18n.NewError(i18n.MsgCursorInvalid)
+	}
+
+	if cursor.Sort != SortKey(order) || len(cursor.Keys) != len(order) {
+		return cursor, i18n.NewError(i18n.MsgCursorSort)
+	}
+
+	for i, item := range order {
+		value, err := cursorValue(cursor.Keys[i], columns[item.Field])
+		if err != nil {
+			return cursor, i18n.NewError(i18n.MsgCursorInvalid)
+		}
+		cursor.Keys[i] = value
+	}
+
+	return cursor, nil
+}
+
+// SortKey возвращает сортировку в виде строки параметра sort
+func SortKey(order []model.OrderInfo) string {
+	fields := Map(order, func(p model.OrderInfo) string {
+		if p.Desc {
+			return "-" + p.Field
+		}
+		return p.Field
+	})
+	return strings.Join(fields, ",")
+}
+
+// ReverseOrder меняет направление всех полей сортировки
+func ReverseOrder(order []model.OrderInfo) []model.OrderInfo {
+	return Map(order, func(p model.OrderInfo) model.OrderInfo {
+		return model.OrderInfo{Field: p.Field, Desc: !p.Desc}
+	})
+}
+
+// Keyset добавляет условие чтения строк после границы курсора в порядке order:
+// (a > ?) OR (a = ? AND b > ?) ... с учетом направления каждого поля.
+// Курсор должен быть получен из DecodeCursor, порядок order - уже развернут для чтения предыдущей страницы.
+func (qb *QueryBuilder) Keyset(cursor *Cursor, order []model.OrderInfo, columns ListFields) *QueryBuilder {
+	if cursor == nil {
+		return qb
+	}
+
+	if len(cursor.Keys) != len(order) {
+		qb.fail(fmt.Errorf("курсор содержит %d значений для %d полей сортировки", len(cursor.Keys), len(order)))
+		return qb
+	}
+
+	var terms []string
+	var args []any
+	for i := range order {
+		var parts []string
+		for j := 0; j <= i; j++ {
+			field, ok := columns[order[j].Field]
+			if !ok {
+				qb.fail(fmt.Errorf("сортировка по полю %q не поддерживается", order[j].Field))
+				return qb
+			}
+
+			op := "="
+			if j == i {
+				op = ">"
+				if order[j].Desc {
+					op = "<"
+				}
+			}
+			parts = append(parts, fmt.Sprintf("%s %s ?", field.Column, op))
+			args = append(args, cursor.Keys[j])
+		}
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return qb.Where(strings.Join(terms, " OR "), args...)
+}
+
+// cursorValue приводит значение из JSON курсора к типу поля
+func cursorValue(value any, field ListField) (any, error) {
+	switch field.Type {
+	case FieldNumber:
+		if number, ok := value.(float64); ok {
+			return number, nil
+		}
+	case FieldInteger:
+		if number, ok := value.(float64); ok && number == math.Trunc(number) {
+			return int64(number), nil
+		}
+	case FieldTime:
+		if text, ok := value.(string); ok {
+			return time.Parse(time.RFC3339Nano, text)
+		}
+	default:
+		if text, ok := value.(string); ok {
+			return text, nil
+		}
+	}
+	return nil, fmt.Errorf("неверное значение курсора %v", value)
+}
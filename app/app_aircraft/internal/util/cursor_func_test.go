@@ -0,0 +1,113 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestCursorKeyset тестирует курсор страницы и условие чтения по ключу
+func TestCursorKeyset(t *testing.T) {
+	columns := ListFields{
+		"range":     {Column: "range", Type: FieldNumber},
+		"code":      {Column: "aircraft_code"},
+		"departure": {Column: "scheduled_departure", Type: FieldTime},
+	}
+	departure := time.Date(2017, 8, 15, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		order  []model.OrderInfo
+		cursor Cursor
+		query  string
+		args   []any
+	}{
+		{"Cursor_Next", []model.OrderInfo{{Field: "range", Desc: true}, {Field: "code"}},
+			Cursor{Keys: []any{5700, "320"}},
+			`select * from t WHERE ((range < $1) OR (range = $2 AND aircraft_code > $3)) ORDER BY range DESC,aircraft_code`,
+			[]any{5700.0, 5700.0, "320"}},
+		{"Cursor_Prev", []model.OrderInfo{{Field: "range", Desc: true}, {Field: "code"}},
+			Cursor{Keys: []any{5700, "320"}, Prev: true},
+			`select * from t WHERE ((range > $1) OR (range = $2 AND aircraft_code < $3)) ORDER BY range,aircraft_code DESC`,
+			[]any{5700.0, 5700.0, "320"}},
+		{"Cursor_Time", []model.OrderInfo{{Field: "departure"}},
+			Cursor{Keys: []any{departure}},
+			`select * from t WHERE ((scheduled_departure > $1)) ORDER BY scheduled_departure`,
+			[]any{departure}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			tt.cursor.Sort = SortKey(tt.order)
+			cursor, err := DecodeCursor(EncodeCursor(tt.cursor), tt.order, columns)
+			if err != nil {
+				t.Fatalf("Ошибка разбора курсора: %v", err)
+			}
+
+			order := tt.order
+			if cursor.Prev {
+				order = ReverseOrder(order)
+			}
+
+			query, args, err := NewQueryBuilder("select * from t").
+				Keyset(&cursor, order, columns).
+				OrderBy(order, columns).
+				Build()
+			if err != nil {
+				t.Fatalf("Ошибка построения запроса: %v", err)
+			}
+
+			if query != tt.query {
+				t.Errorf("Ожидался запрос '%s', получено '%s'", tt.query, query)
+			}
+
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("Ожидались аргументы %v, получено %v", tt.args, args)
+			}
+		})
+	}
+}
+
+// TestDecodeCursorErrors тестирует отказ для поврежденного курсора или другой сортировки
+func TestDecodeCursorErrors(t *testing.T) {
+	columns := ListFields{"range": {Column: "range", Type: FieldNumber}, "code": {Column: "aircraft_code"}}
+	order := []model.OrderInfo{{Field: "code"}}
+
+	tokens := map[string]string{
+		"Cursor_NotBase64":  "***",
+		"Cursor_NotJson":    EncodeCursor(Cursor{})[:2],
+		"Cursor_OtherSort":  EncodeCursor(Cursor{Sort: "-range,code", Keys: []any{1, "319"}}),
+		"Cursor_WrongType":  EncodeCursor(Cursor{Sort: "code", Keys: []any{319}}),
+		"Cursor_KeysLength": EncodeCursor(Cursor{Sort: "code", Keys: []any{"319", "320"}}),
+	}
+
+	for name, token := range tokens {
+		t.Run(name, func(t *testing.T) {
+			if _, err := DecodeCursor(token, order, columns); err == nil {
+				t.Errorf("Ожидалась ошибка разбора курсора '%s'", token)
+			}
+		})
+	}
+}
+
+// TestDecodeCursorInteger тестирует приведение ключа курсора к целому для целого поля
+func TestDecodeCursorInteger(t *testing.T) {
+	columns := ListFields{"range": {Column: "range", Type: FieldInteger}}
+	order := []model.OrderInfo{{Field: "range"}}
+
+	cursor, err := DecodeCursor(EncodeCursor(Cursor{Sort: "range", Keys: []any{6700}}), order, columns)
+	if err != nil {
+		t.Fatalf("Неожиданная ошибка: %v", err)
+	}
+
+	if !reflect.DeepEqual(cursor.Keys, []any{int64(6700)}) {
+		t.Errorf("Ожидался целый ключ, получено %#v", cursor.Keys)
+	}
+
+	if _, err := DecodeCursor(EncodeCursor(Cursor{Sort: "range", Keys: []any{6700.5}}), order, columns); err == nil {
+		t.Error("Ожидалась ошибка для дробного ключа целого поля")
+	}
+}
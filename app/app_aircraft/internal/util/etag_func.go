@@ -0,0 +1,73 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// Version возвращает версию данных: первые 16 байт хеша SHA-256 JSON-представления value.
+// Ключи карт сериализуются по порядку, поэтому одинаковые данные дают одинаковую версию.
+func Version(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:16])
+}
+
+// ETag возвращает сильный тег сущности для заголовка ETag
+func ETag(version string) string {
+	return `"` + version + `"`
+}
+
+// RepresentationETag возвращает слабый тег представления объекта: версию объекта и версию тела ответа
+// через точку. Тег меняется вместе с телом ответа, а версия объекта в нем позволяет передать тег в If-Match.
+func RepresentationETag(version string, representation string) string {
+	return "W/" + ETag(version+"."+representation)
+}
+
+// MatchVersion проверяет условие If-Match для версии объекта: * , сильный тег версии
+// или слабый тег представления той же версии объекта (RepresentationETag)
+func MatchVersion(header string, version string) bool {
+	if version == "" {
+		return false
+	}
+
+	for _, tag := range splitList(header, ",") {
+		if tag == "*" || tag == ETag(version) {
+			return true
+		}
+
+		if value, found := strings.CutPrefix(tag, "W/"); found && strings.HasPrefix(value, `"`+version+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchETag проверяет, совпадает ли версия с условием заголовка If-Match или If-None-Match:
+// список тегов через запятую или * для любой версии. При сильном сравнении (If-Match)
+// слабые теги W/ не совпадают ни с чем, при слабом (If-None-Match) префикс W/ не учитывается.
+func MatchETag(header string, version string, weak bool) bool {
+	for _, tag := range splitList(header, ",") {
+		if tag == "*" {
+			return true
+		}
+
+		if value, found := strings.CutPrefix(tag, "W/"); found {
+			if !weak {
+				continue
+			}
+			tag = value
+		}
+
+		if tag == ETag(version) {
+			return true
+		}
+	}
+	return false
+}
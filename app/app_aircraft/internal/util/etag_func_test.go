@@ -0,0 +1,82 @@
+package util
+
+import (
+	"testing"
+)
+
+// TestVersion тестирует устойчивость версии данных к порядку ключей карт
+func TestVersion(t *testing.T) {
+	first := Version(map[string]string{"ru": "Москва", "en": "Moscow"})
+	second := Version(map[string]string{"en": "Moscow", "ru": "Москва"})
+
+	if first == "" || first != second {
+		t.Errorf("Ожидались одинаковые версии, получено '%s' и '%s'", first, second)
+	}
+
+	if changed := Version(map[string]string{"ru": "Москва", "en": "Moskva"}); changed == first {
+		t.Errorf("Ожидалась другая версия измененных данных, получено '%s'", changed)
+	}
+
+	if len(first) != 32 {
+		t.Errorf("Ожидалась версия из 32 символов, получено '%s'", first)
+	}
+}
+
+// TestMatchETag тестирует сравнение версии с заголовками If-Match и If-None-Match
+func TestMatchETag(t *testing.T) {
+	version := "3f2a"
+
+	tests := []struct {
+		name     string
+		header   string
+		weak     bool
+		expected bool
+	}{
+		{"Empty", "", false, false},
+		{"Strong", `"3f2a"`, false, true},
+		{"Strong_Mismatch", `"0000"`, false, false},
+		{"Strong_List", `"0000", "3f2a"`, false, true},
+		{"Strong_Any", "*", false, true},
+		{"Strong_WeakTag", `W/"3f2a"`, false, false},
+		{"Strong_Unquoted", "3f2a", false, false},
+		{"Weak_WeakTag", `W/"3f2a"`, true, true},
+		{"Weak_List", `"0000",W/"3f2a"`, true, true},
+		{"Weak_Mismatch", `W/"0000"`, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := MatchETag(tt.header, version, tt.weak); result != tt.expected {
+				t.Errorf("Для '%s' ожидалось %v, получено %v", tt.header, tt.expected, result)
+			}
+		})
+	}
+}
+
+// TestMatchVersion тестирует условие If-Match с тегами ответа изменения и ответа чтения
+func TestMatchVersion(t *testing.T) {
+	version := "3f2a"
+
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"Empty", "", false},
+		{"Any", "*", true},
+		{"Version", `"3f2a"`, true},
+		{"Representation", RepresentationETag(version, "9b1c"), true},
+		{"Representation_List", `"0000", ` + RepresentationETag(version, "9b1c"), true},
+		{"Representation_OtherVersion", RepresentationETag("0000", "9b1c"), false},
+		{"Representation_Prefix", RepresentationETag("3f2a0", "9b1c"), false},
+		{"WeakVersion", `W/"3f2a"`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := MatchVersion(tt.header, version); result != tt.expected {
+				t.Errorf("Для '%s' ожидалось %v, получено %v", tt.header, tt.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package util
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+)
+
+// Значение параметра lang для ответа со всеми языками
+const LangAll = "all"
+
+// Языки, которыми заканчивается цепочка выбора, если предпочтительных языков нет в данных
+var DefaultLanguages = []string{"en", "ru"}
+
+// Тег языка BCP 47: основной язык и необязательные подтеги, например de, en-US, zh-Hans-CN
+var languageTag = regexp.MustCompile(`^[a-zA-Z]{2,8}(-[a-zA-Z0-9]{1,8})*$`)
+
+// ParseLangParam разбирает параметр lang: список тегов через запятую в порядке предпочтения или all
+func ParseLangParam(value string) ([]string, error) {
+	var tags []string
+	for _, tag := range splitList(value, ",") {
+		if strings.EqualFold(tag, LangAll) {
+			return []string{LangAll}, nil
+		}
+		if !languageTag.MatchString(tag) {
+			return nil, i18n.NewError(i18n.MsgRequestLang, tag)
+		}
+		tags = append(tags, strings.ToLower(tag))
+	}
+	return tags, nil
+}
+
+// ParseAcceptLanguage разбирает заголовок Accept-Language, например "de-AT,de;q=0.9,en;q=0.5".
+// Теги возвращаются по убыванию веса q, теги с q=0, "*" и неверные теги пропускаются.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var items []weighted
+	for _, part := range splitList(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		if q <= 0 || !languageTag.MatchString(tag) {
+			continue
+		}
+		items = append(items, weighted{tag: strings.ToLower(tag), q: q})
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	return Map(items, func(p weighted) string {
+		return p.tag
+	})
+}
+
+// LanguageChain возвращает цепочку выбора языка: каждый тег, затем его основной язык
+// (de-at -> de), затем языки по умолчанию, без повторов
+func LanguageChain(preferred []string) []string {
+	var chain []string
+	add := func(tag string) {
+		if !slices.Contains(chain, tag) {
+			chain = append(chain, tag)
+		}
+	}
+
+	for _, tag := range preferred {
+		add(tag)
+		if base, _, found := strings.Cut(tag, "-"); found {
+			add(base)
+		}
+	}
+	for _, tag := range DefaultLanguages {
+		add(tag)
+	}
+
+	return chain
+}
+
+// Localize выбирает значение по цепочке языков. Если ни одного языка цепочки нет,
+// возвращается значение на первом по алфавиту языке, чтобы ответ не был пустым.
+func Localize(values map[string]string, chain []string) (string, string) {
+	for _, tag := range chain {
+		if value := values[tag]; value != "" {
+			return value, tag
+		}
+	}
+
+	tags := make([]string, 0, len(values))
+	for tag, value := range values {
+		if value != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return "", ""
+	}
+
+	slices.Sort(tags)
+	return values[tags[0]], tags[0]
+}
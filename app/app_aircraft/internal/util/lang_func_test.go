@@ -0,0 +1,84 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseAcceptLanguage тестирует разбор заголовка Accept-Language
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected []string
+	}{
+		{"Accept_Empty", "", nil},
+		{"Accept_Single", "de", []string{"de"}},
+		{"Accept_Weights", "en;q=0.5, de-AT, de;q=0.9", []string{"de-at", "de", "en"}},
+		{"Accept_SkipInvalid", "*;q=0.1, fr;q=0, zh-Hans-CN;q=0.8, 1x, es;q=abc", []string{"zh-hans-cn"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tags := ParseAcceptLanguage(tt.header); !reflect.DeepEqual(tags, tt.expected) {
+				t.Errorf("Ожидалось %v, получено %v", tt.expected, tags)
+			}
+		})
+	}
+}
+
+// TestParseLangParam тестирует разбор параметра lang
+func TestParseLangParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+		valid    bool
+	}{
+		{"Lang_Empty", "", nil, true},
+		{"Lang_List", "de, EN-us", []string{"de", "en-us"}, true},
+		{"Lang_All", "ALL", []string{LangAll}, true},
+		{"Lang_Invalid", "de,e n", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := ParseLangParam(tt.value)
+			if (err == nil) != tt.valid {
+				t.Fatalf("Ожидалась корректность %v, получена ошибка %v", tt.valid, err)
+			}
+			if !reflect.DeepEqual(tags, tt.expected) {
+				t.Errorf("Ожидалось %v, получено %v", tt.expected, tags)
+			}
+		})
+	}
+}
+
+// TestLocalize тестирует выбор значения по цепочке языков
+func TestLocalize(t *testing.T) {
+	values := map[string]string{"ru": "Москва", "en": "Moscow", "de": "Moskau", "zh": ""}
+
+	tests := []struct {
+		name      string
+		values    map[string]string
+		preferred []string
+		value     string
+		lang      string
+	}{
+		{"Localize_Exact", values, []string{"de"}, "Moskau", "de"},
+		{"Localize_Base", values, []string{"de-at"}, "Moskau", "de"},
+		{"Localize_EmptyValue", values, []string{"zh"}, "Moscow", "en"},
+		{"Localize_Default", values, []string{"fr"}, "Moscow", "en"},
+		{"Localize_Any", map[string]string{"fr": "Moscou", "de": "Moskau"}, []string{"es"}, "Moskau", "de"},
+		{"Localize_None", map[string]string{}, []string{"ru"}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, lang := Localize(tt.values, LanguageChain(tt.preferred))
+			if value != tt.value || lang != tt.lang {
+				t.Errorf("Ожидалось '%s' (%s), получено '%s' (%s)", tt.value, tt.lang, value, lang)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package util
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Допустимые классы обслуживания (fare_conditions)
+var seatClasses = []string{"Business", "Comfort", "Economy"}
+
+// Наибольший номер ряда: номер места bookings.seats.seat_no - не более трех цифр ряда и буква
+const maxSeatRow = 999
+
+// ParseSeatLayout разворачивает описание компоновки салона в список мест.
+// Формат: "Business rows 1-5 A,C,D,F; Economy rows 6-30 A-F".
+// Каждая секция состоит из класса, ключевого слова rows, диапазона рядов
+// и списка букв мест (через запятую, допускаются диапазоны вида A-F).
+func ParseSeatLayout(spec string) ([]model.SeatItemData, error) {
+
+	var seats []model.SeatItemData
+	used := make(map[string]string)
+
+	sections := Filter(strings.Split(spec, ";"), func(p string) bool {
+		return strings.TrimSpace(p) != ""
+	})
+
+	if len(sections) == 0 {
+		return nil, i18n.NewError(i18n.MsgLayoutEmpty)
+	}
+
+	for _, section := range sections {
+		parts := strings.Fields(section)
+		if len(parts) != 4 || !strings.EqualFold(parts[1], "rows") {
+			return nil, i18n.NewError(i18n.MsgLayoutSection, strings.TrimSpace(section))
+		}
+
+		seatClass, err := parseSeatClass(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		rowFrom, rowTo, err := parseRowRange(parts[2])
+		if err != nil {
+			return nil, err
+		}
+
+		letters, err := parseSeatLetters(parts[3])
+		if err != nil {
+			return nil, err
+		}
+
+		for row := rowFrom; row <= rowTo; row++ {
+			for _, letter := range letters {
+				seatNo := fmt.Sprintf("%d%s", row, letter)
+				if prev, exists := used[seatNo]; exists {
+					return nil, i18n.NewError(i18n.MsgLayoutSeatRepeated, seatNo, prev, seatClass)
+				}
+				used[seatNo] = seatClass
+				seats = append(seats, model.SeatItemData{SeatNo: seatNo, SeatType: seatClass})
+			}
+		}
+	}
+
+	return seats, nil
+}
+
+// DiffSeatLayout сравнивает текущую и новую схемы мест:
+// добавленные, удаленные и сменившие класс места
+func DiffSeatLayout(current []model.SeatItemData, target []model.SeatItemData) (added []model.SeatItemData,
+	removed []model.SeatItemData, changed []model.SeatItemData) {
+
+	currentMap := make(map[string]string, len(current))
+	for _, seat := range current {
+		currentMap[seat.SeatNo] = seat.SeatType
+	}
+
+	targetMap := make(map[string]string, len(target))
+	for _, seat := range target {
+		targetMap[seat.SeatNo] = seat.SeatType
+
+		seatType, exists := currentMap[seat.SeatNo]
+		if !exists {
+			added = append(added, seat)
+		} else if seatType != seat.SeatType {
+			changed = append(changed, seat)
+		}
+	}
+
+	for _, seat := range current {
+		if _, exists := targetMap[seat.SeatNo]; !exists {
+			removed = append(removed, seat)
+		}
+	}
+
+	return added, removed, changed
+}
+
+// CountSeatTypes подсчитывает места по классам, упорядочивая классы по названию
+func CountSeatTypes(seats []model.SeatItemData) []model.SeatData {
+
+	seatMap := SliceToMap(seats, func(p model.SeatItemData) string {
+		return p.SeatType
+	})
+
+	seatTypes := make([]model.SeatData, 0, len(seatMap))
+	for seatType, items := range seatMap {
+		seatTypes = append(seatTypes, model.SeatData{SeatType: seatType, Count: len(items)})
+	}
+
+	sort.Slice(seatTypes, func(i, j int) bool {
+		return seatTypes[i].SeatType < seatTypes[j].SeatType
+	})
+
+	return seatTypes
+}
+
+func parseSeatClass(value string) (string, error) {
+	for _, seatClass := range seatClasses {
+		if strings.EqualFold(seatClass, value) {
+			return seatClass, nil
+		}
+	}
+	return "", i18n.NewError(i18n.MsgLayoutClass, value, strings.Join(seatClasses, ", "))
+}
+
+func parseRowRange(value string) (int, int, error) {
+	bounds := strings.SplitN(value, "-", 2)
+
+	rowFrom, err := strconv.Atoi(bounds[0])
+	if err != nil || rowFrom < 1 {
+		return 0, 0, i18n.NewError(i18n.MsgLayoutRow, bounds[0])
+	}
+
+	rowTo := rowFrom
+	if len(bounds) == 2 {
+		rowTo, err = strconv.Atoi(bounds[1])
+		if err != nil || rowTo < rowFrom {
+			return 0, 0, i18n.NewError(i18n.MsgLayoutRows, value)
+		}
+	}
+
+	// Граница проверяется до разворачивания мест, чтобы не создавать миллионы мест
+	if rowTo > maxSeatRow {
+		return 0, 0, i18n.NewError(i18n.MsgLayoutRows, value)
+	}
+
+	return rowFrom, rowTo, nil
+}
+
+func parseSeatLetters(value string) ([]string, error) {
+	var letters []string
+	used := make(map[byte]bool)
+
+	for _, item := range strings.Split(value, ",") {
+		bounds := strings.SplitN(strings.ToUpper(item), "-", 2)
+		if len(bounds) == 1 {
+			bounds = append(bounds, bounds[0])
+		}
+
+		if len(bounds[0]) != 1 || len(bounds[1]) != 1 ||
+			bounds[0][0] < 'A' || bounds[1][0] > 'Z' || bounds[0][0] > bounds[1][0] {
+			return nil, i18n.NewError(i18n.MsgLayoutSeats, item)
+		}
+
+		for letter := bounds[0][0]; letter <= bounds[1][0]; letter++ {
+			if used[letter] {
+				return nil, i18n.NewError(i18n.MsgLayoutLetter, letter, value)
+			}
+			used[letter] = true
+			letters = append(letters, string(letter))
+		}
+	}
+
+	return letters, nil
+}
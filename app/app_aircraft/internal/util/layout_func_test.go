@@ -0,0 +1,90 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestParseSeatLayout тестирует разбор описания компоновки салона
+func TestParseSeatLayout(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		count   int
+		classes map[string]int
+		failed  bool
+	}{
+		{"Layout_TwoClasses", "Business rows 1-5 A,C,D,F; Economy rows 6-30 A-F", 170,
+			map[string]int{"Business": 20, "Economy": 150}, false},
+		{"Layout_SingleRow", "comfort rows 7 A-C,D-F", 6, map[string]int{"Comfort": 6}, false},
+		{"Layout_TrailingSemicolon", "Economy rows 1-2 A;", 2, map[string]int{"Economy": 2}, false},
+		{"Layout_Empty", " ; ", 0, nil, true},
+		{"Layout_UnknownClass", "First rows 1-2 A", 0, nil, true},
+		{"Layout_BadRows", "Economy rows 5-1 A", 0, nil, true},
+		{"Layout_LastRow", "Economy rows 999 A", 1, map[string]int{"Economy": 1}, false},
+		{"Layout_TooManyRows", "Economy rows 1-99999999 A-K", 0, nil, true},
+		{"Layout_RowTooLarge", "Economy rows 1000 A", 0, nil, true},
+		{"Layout_BadLetters", "Economy rows 1 F-A", 0, nil, true},
+		{"Layout_Overlap", "Business rows 1-2 A; Economy rows 2-3 A", 0, nil, true},
+		{"Layout_NoKeyword", "Economy 1-2 A", 0, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			seats, err := ParseSeatLayout(tt.spec)
+
+			if tt.failed {
+				if err == nil {
+					t.Errorf("Ожидалась ошибка разбора '%s'", tt.spec)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Ошибка разбора '%s': %v", tt.spec, err)
+			}
+
+			if len(seats) != tt.count {
+				t.Errorf("Ожидалось %v мест, получено %v", tt.count, len(seats))
+			}
+
+			for _, st := range CountSeatTypes(seats) {
+				if tt.classes[st.SeatType] != st.Count {
+					t.Errorf("Класс '%v': ожидалось %v мест, получено %v", st.SeatType, tt.classes[st.SeatType], st.Count)
+				}
+			}
+		})
+	}
+}
+
+// TestDiffSeatLayout тестирует сравнение схем мест
+func TestDiffSeatLayout(t *testing.T) {
+
+	current := []model.SeatItemData{
+		{SeatNo: "1A", SeatType: "Business"},
+		{SeatNo: "1B", SeatType: "Business"},
+		{SeatNo: "2A", SeatType: "Economy"},
+	}
+
+	target := []model.SeatItemData{
+		{SeatNo: "1A", SeatType: "Business"},
+		{SeatNo: "2A", SeatType: "Comfort"},
+		{SeatNo: "3A", SeatType: "Economy"},
+	}
+
+	added, removed, changed := DiffSeatLayout(current, target)
+
+	if len(added) != 1 || added[0].SeatNo != "3A" {
+		t.Errorf("Неверный список добавленных мест: %v", added)
+	}
+
+	if len(removed) != 1 || removed[0].SeatNo != "1B" {
+		t.Errorf("Неверный список удаленных мест: %v", removed)
+	}
+
+	if len(changed) != 1 || changed[0].SeatNo != "2A" || changed[0].SeatType != "Comfort" {
+		t.Errorf("Неверный список измененных мест: %v", changed)
+	}
+}
@@ -0,0 +1,165 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Тип поля списка, определяет допустимые операции фильтра
+type FieldType int
+
+const (
+	FieldString FieldType = iota
+	FieldNumber
+	FieldInteger
+	FieldTime
+)
+
+// Поле списка, доступное для сортировки и фильтра
+type ListField struct {
+	Column string // выражение SQL
+	Type   FieldType
+}
+
+// Белый список полей ресурса: имя поля в API -> поле
+type ListFields map[string]ListField
+
+// Операции фильтра, двухсимвольные проверяются первыми
+var filterOps = []string{">=", "<=", "!=", "=", ">", "<", "~"}
+
+// ParseListQuery разбирает параметры sort и filter и проверяет поля по белому списку ресурса.
+// Ошибки возвращаются сообщениями валидации со свойством sort или filter.
+func ParseListQuery(query model.ListQuery, fields ListFields) (model.ListOptions, []model.Validation) {
+	var options model.ListOptions
+	var validations []model.Validation
+
+	for _, item := range splitList(query.Sort, ",") {
+		order := model.OrderInfo{Field: strings.TrimPrefix(item, "+")}
+		if strings.HasPrefix(item, "-") {
+			order = model.OrderInfo{Field: item[1:], Desc: true}
+		}
+
+		if _, ok := fields[order.Field]; !ok {
+			validations = append(validations, i18n.Validation("sort", i18n.MsgListSort, order.Field))
+			continue
+		}
+		options.Order = append(options.Order, order)
+	}
+
+	for _, item := range splitList(query.Filter, ";") {
+		filter, err := parseFilter(item)
+		if err != nil {
+			validations = append(validations, i18n.ValidationOf("filter", err))
+			continue
+		}
+
+		field, ok := fields[filter.Field]
+		if !ok {
+			validations = append(validations, i18n.Validation("filter", i18n.MsgListFilter, filter.Field))
+			continue
+		}
+
+		if err := checkFilter(filter, field); err != nil {
+			validations = append(validations, i18n.ValidationOf("filter", err))
+			continue
+		}
+		options.Filters = append(options.Filters, filter)
+	}
+
+	return options, validations
+}
+
+// FilterCondition возвращает условие SQL с плейсхолдером ? и аргумент для условия фильтра
+func FilterCondition(filter model.FilterInfo, fields ListFields) (string, any, error) {
+	field, ok := fields[filter.Field]
+	if !ok {
+		return "", nil, fmt.Errorf("фильтр по полю %q не поддерживается", filter.Field)
+	}
+
+	if err := checkFilter(filter, field); err != nil {
+		return "", nil, err
+	}
+
+	if filter.Op == "~" {
+		return field.Column + ` ILIKE ?`, "%" + escapeLike(filter.Value) + "%", nil
+	}
+
+	// Значение передается аргументом типа столбца, чтобы сравнение выполнялось без приведения к numeric
+	var value any = filter.Value
+	switch field.Type {
+	case FieldNumber:
+		value, _ = strconv.ParseFloat(filter.Value, 64)
+	case FieldInteger:
+		value, _ = strconv.ParseInt(filter.Value, 10, 64)
+	}
+	return fmt.Sprintf("%s %s ?", field.Column, filter.Op), value, nil
+}
+
+// parseFilter разбирает выражение вида поле<операция>значение
+func parseFilter(item string) (model.FilterInfo, error) {
+	end := strings.IndexFunc(item, func(r rune) bool {
+		return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	})
+	if end <= 0 {
+		return model.FilterInfo{}, i18n.NewError(i18n.MsgFilterExpression, item)
+	}
+
+	rest := item[end:]
+	for _, op := range filterOps {
+		if strings.HasPrefix(rest, op) {
+			value := strings.TrimSpace(rest[len(op):])
+			if value == "" {
+				return model.FilterInfo{}, i18n.NewError(i18n.MsgFilterValue, item)
+			}
+			return model.FilterInfo{Field: item[:end], Op: op, Value: value}, nil
+		}
+	}
+
+	return model.FilterInfo{}, i18n.NewError(i18n.MsgFilterOperation, item)
+}
+
+// checkFilter проверяет применимость операции и значения к типу поля
+func checkFilter(filter model.FilterInfo, field ListField) error {
+	switch field.Type {
+	case FieldNumber:
+		if filter.Op == "~" {
+			return i18n.NewError(i18n.MsgFilterLikeNumber, filter.Field)
+		}
+		if _, err := strconv.ParseFloat(filter.Value, 64); err != nil {
+			return i18n.NewError(i18n.MsgFilterNumber, filter.Field, filter.Value)
+		}
+	case FieldInteger:
+		if filter.Op == "~" {
+			return i18n.NewError(i18n.MsgFilterLikeNumber, filter.Field)
+		}
+		if _, err := strconv.ParseInt(filter.Value, 10, 64); err != nil {
+			return i18n.NewError(i18n.MsgFilterInteger, filter.Field, filter.Value)
+		}
+	case FieldTime:
+		if filter.Op == "~" {
+			return i18n.NewError(i18n.MsgFilterLikeDate, filter.Field)
+		}
+		if _, err := time.Parse(time.RFC3339, filter.Value); err != nil {
+			return i18n.NewError(i18n.MsgFilterDate, filter.Field, filter.Value)
+		}
+	}
+	return nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// splitList разбивает список по разделителю, пропуская пустые элементы
+func splitList(value string, sep string) []string {
+	items := Map(strings.Split(value, sep), strings.TrimSpace)
+	return Filter(items, func(p string) bool {
+		return p != ""
+	})
+}
@@ -0,0 +1,94 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestParseListQuery тестирует разбор параметров сортировки и фильтра
+func TestParseListQuery(t *testing.T) {
+	fields := ListFields{
+		"code":   {Column: "aircraft_code"},
+		"nameEn": {Column: "model->>'en'"},
+		"range":  {Column: "range", Type: FieldInteger},
+		"weight": {Column: "weight", Type: FieldNumber},
+	}
+
+	tests := []struct {
+		name        string
+		query       model.ListQuery
+		options     model.ListOptions
+		validations []string
+	}{
+		{"List_Empty", model.ListQuery{}, model.ListOptions{}, nil},
+		{"List_SortAndFilter",
+			model.ListQuery{Sort: "-range, code", Filter: "range>=3000;nameEn~boeing"},
+			model.ListOptions{
+				Order:   []model.OrderInfo{{Field: "range", Desc: true}, {Field: "code"}},
+				Filters: []model.FilterInfo{{Field: "range", Op: ">=", Value: "3000"}, {Field: "nameEn", Op: "~", Value: "boeing"}},
+			}, nil},
+		{"List_NotEqual", model.ListQuery{Filter: "code!=319;"},
+			model.ListOptions{Filters: []model.FilterInfo{{Field: "code", Op: "!=", Value: "319"}}}, nil},
+		{"List_UnknownFields", model.ListQuery{Sort: "seats", Filter: "speed>900"},
+			model.ListOptions{}, []string{"sort", "filter"}},
+		{"List_BadNumber", model.ListQuery{Filter: "range>far"}, model.ListOptions{}, []string{"filter"}},
+		{"List_FractionalInteger", model.ListQuery{Filter: "range=5000.5"}, model.ListOptions{}, []string{"filter"}},
+		{"List_FractionalNumber", model.ListQuery{Filter: "weight<=41.5"},
+			model.ListOptions{Filters: []model.FilterInfo{{Field: "weight", Op: "<=", Value: "41.5"}}}, nil},
+		{"List_LikeOnNumber", model.ListQuery{Filter: "range~30"}, model.ListOptions{}, []string{"filter"}},
+		{"List_BadExpression", model.ListQuery{Filter: "range;>=5;code"}, model.ListOptions{}, []string{"filter", "filter", "filter"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			options, validations := ParseListQuery(tt.query, fields)
+
+			var properties []string
+			for _, validation := range validations {
+				properties = append(properties, validation.Property)
+			}
+
+			if !reflect.DeepEqual(properties, tt.validations) {
+				t.Fatalf("Ожидались ошибки %v, получено %v", tt.validations, validations)
+			}
+
+			if !reflect.DeepEqual(options, tt.options) {
+				t.Errorf("Ожидались параметры %+v, получено %+v", tt.options, options)
+			}
+		})
+	}
+}
+
+// TestFilterCondition тестирует построение условий фильтра
+func TestFilterCondition(t *testing.T) {
+	fields := ListFields{
+		"nameEn": {Column: "model->>'en'"},
+		"range":  {Column: "range", Type: FieldInteger},
+		"weight": {Column: "weight", Type: FieldNumber},
+	}
+
+	query, args, err := NewQueryBuilder("select * from t").
+		Filter([]model.FilterInfo{{Field: "range", Op: ">=", Value: "3000"}, {Field: "nameEn", Op: "~", Value: "50%_off"},
+			{Field: "weight", Op: "<", Value: "41.5"}}, fields).
+		Build()
+	if err != nil {
+		t.Fatalf("Ошибка построения запроса: %v", err)
+	}
+
+	expected := `select * from t WHERE (range >= $1) AND (model->>'en' ILIKE $2) AND (weight < $3)`
+	if query != expected {
+		t.Errorf("Ожидался запрос '%s', получено '%s'", expected, query)
+	}
+
+	// Целое поле сравнивается с целым аргументом
+	if !reflect.DeepEqual(args, []any{int64(3000), `%50\%\_off%`, 41.5}) {
+		t.Errorf("Неверные аргументы %v", args)
+	}
+
+	if _, _, err := FilterCondition(model.FilterInfo{Field: "range", Op: "=", Value: "5000.5"}, fields); err == nil {
+		t.Error("Ожидалась ошибка для дробного значения целого поля")
+	}
+}
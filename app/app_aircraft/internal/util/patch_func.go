@@ -0,0 +1,69 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+)
+
+// MergePatch применяет к target документ изменений JSON Merge Patch (RFC 7396): поля patch заменяют
+// поля target, вложенные объекты объединяются рекурсивно, null удаляет поле. Документ изменений должен
+// быть объектом, поля, которых нет в T, и значения неверного типа возвращаются ошибкой.
+func MergePatch[T any](target T, patch []byte) (T, error) {
+	var result T
+
+	var changes any
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return result, err
+	}
+	if _, ok := changes.(map[string]any); !ok {
+		return result, i18n.NewError(i18n.MsgPatchObject)
+	}
+
+	data, err := json.Marshal(target)
+	if err != nil {
+		return result, err
+	}
+
+	var document any
+	if err := json.Unmarshal(data, &document); err != nil {
+		return result, err
+	}
+
+	data, err = json.Marshal(mergePatch(document, changes))
+	if err != nil {
+		return result, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// mergePatch объединяет значение target с изменениями patch по правилам RFC 7396
+func mergePatch(target any, patch any) any {
+	changes, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	document, ok := target.(map[string]any)
+	if !ok {
+		document = map[string]any{}
+	}
+
+	for key, value := range changes {
+		if value == nil {
+			delete(document, key)
+			continue
+		}
+		document[key] = mergePatch(document[key], value)
+	}
+
+	return document
+}
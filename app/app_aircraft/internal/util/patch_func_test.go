@@ -0,0 +1,60 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestMergePatch тестирует применение JSON Merge Patch к входным данным самолета
+func TestMergePatch(t *testing.T) {
+	aircraft := model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+		Names: map[string]string{"de": "Boeing 777-300", "fr": "Boeing 777-300"}, Range: 11100}
+
+	tests := []struct {
+		name     string
+		patch    string
+		expected *model.AircraftInput
+	}{
+		{"Range", `{"range": 11200}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+				Names: map[string]string{"de": "Boeing 777-300", "fr": "Boeing 777-300"}, Range: 11200}},
+		{"NameKeys", `{"names": {"de": "Boeing 777-300 (DE)", "fr": null, "it": "Boeing 777-300"}}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+				Names: map[string]string{"de": "Boeing 777-300 (DE)", "it": "Boeing 777-300"}, Range: 11100}},
+		{"RemoveNames", `{"names": null, "nameEn": null}`,
+			&model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", Range: 11100}},
+		{"Empty", `{}`, &aircraft},
+		{"UnknownField", `{"seats": 402}`, nil},
+		{"WrongType", `{"range": "far"}`, nil},
+		{"NotObject", `[{"op": "replace", "path": "/range", "value": 11200}]`, nil},
+		{"Invalid", `{"range":`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := MergePatch(aircraft, []byte(tt.patch))
+
+			if tt.expected == nil {
+				if err == nil {
+					t.Errorf("Ожидалась ошибка, получено %+v", result)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Неожиданная ошибка: %v", err)
+			}
+
+			if !reflect.DeepEqual(result, *tt.expected) {
+				t.Errorf("Ожидалось %+v, получено %+v", *tt.expected, result)
+			}
+		})
+	}
+
+	// Исходные данные не изменяются
+	if len(aircraft.Names) != 2 || aircraft.Range != 11100 {
+		t.Errorf("Изменены исходные данные: %+v", aircraft)
+	}
+}
@@ -2,69 +2,224 @@ package util
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+
 	"github.com/snpavlov/app_aircraft/internal/model"
 )
 
-func AddWhereClause(srcquery string, fields []string, startargpos int, keyword string, logic string) (string) {
-	i := startargpos
-	sfields := Map(fields, func(p string) string {
-		sfield := fmt.Sprintf("\"%s\" = $%v", p, i)
-		i++
-		return sfield
-    })
-	scondition := strings.Join(sfields, logic)
-	return fmt.Sprintf("%s %s (%s) ", srcquery, keyword, scondition) 
+// Стиль плейсхолдеров параметров запроса
+type PlaceholderStyle int
+
+const (
+	PlaceholderDollar   PlaceholderStyle = iota // $1, $2 ... для database/sql и pgx
+	PlaceholderQuestion                         // ? для gorm Raw
+)
+
+// QueryBuilder собирает запрос из базового SELECT и условий.
+// Значения никогда не попадают в текст запроса: для них формируются плейсхолдеры
+// и список аргументов. Имена колонок задаются кодом репозитория,
+// колонки ORDER BY из запроса клиента проверяются по белому списку.
+type QueryBuilder struct {
+	base    string
+	style   PlaceholderStyle
+	where   []string
+	groupBy []string
+	orderBy []string
+	limit   string
+	offset  string
+	args    []any
+	err     error
+}
+
+// NewQueryBuilder создает построитель с плейсхолдерами $n
+func NewQueryBuilder(base string) *QueryBuilder {
+	return &QueryBuilder{base: base, style: PlaceholderDollar}
+}
+
+// Placeholders задает стиль плейсхолдеров, вызывается до добавления условий
+func (qb *QueryBuilder) Placeholders(style PlaceholderStyle) *QueryBuilder {
+	qb.style = style
+	return qb
+}
+
+// Param добавляет аргумент и возвращает его плейсхолдер
+func (qb *QueryBuilder) Param(value any) string {
+	qb.args = append(qb.args, value)
+	if qb.style == PlaceholderQuestion {
+		return "?"
+	}
+	return "$" + strconv.Itoa(len(qb.args))
+}
+
+// Params добавляет аргументы и возвращает список плейсхолдеров через запятую.
+// Для пустого списка возвращается NULL, чтобы IN (NULL) не находил строк.
+func (qb *QueryBuilder) Params(values []any) string {
+	if len(values) == 0 {
+		return "NULL"
+	}
+	placeholders := Map(values, qb.Param)
+	return strings.Join(placeholders, ",")
+}
+
+// Where добавляет условие, каждый знак ? в условии заменяется плейсхолдером следующего аргумента
+func (qb *QueryBuilder) Where(condition string, args ...any) *QueryBuilder {
+	if strings.Count(condition, "?") != len(args) {
+		qb.fail(fmt.Errorf("число аргументов %d не соответствует условию %q", len(args), condition))
+		return qb
+	}
+
+	var sb strings.Builder
+	i := 0
+	for _, r := range condition {
+		if r == '?' {
+			sb.WriteString(qb.Param(args[i]))
+			i++
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	qb.where = append(qb.where, "("+sb.String()+")")
+	return qb
+}
+
+// WhereEq добавляет условие равенства колонки значению
+func (qb *QueryBuilder) WhereEq(column string, value any) *QueryBuilder {
+	qb.where = append(qb.where, fmt.Sprintf("(%s = %s)", QuoteIdent(column), qb.Param(value)))
+	return qb
+}
+
+// WhereIn добавляет условие вхождения колонки в список значений
+func (qb *QueryBuilder) WhereIn(column string, values []any) *QueryBuilder {
+	qb.where = append(qb.where, fmt.Sprintf("(%s IN (%s))", QuoteIdent(column), qb.Params(values)))
+	return qb
+}
+
+// GroupBy добавляет колонки группировки
+func (qb *QueryBuilder) GroupBy(columns ...string) *QueryBuilder {
+	qb.groupBy = append(qb.groupBy, Map(columns, QuoteIdent)...)
+	return qb
+}
+
+// Filter добавляет условия фильтра списка. Поле, которого нет в белом списке columns, делает запрос ошибочным.
+func (qb *QueryBuilder) Filter(filters []model.FilterInfo, columns ListFields) *QueryBuilder {
+	for _, filter := range filters {
+		condition, arg, err := FilterCondition(filter, columns)
+		if err != nil {
+			qb.fail(err)
+			return qb
+		}
+		qb.Where(condition, arg)
+	}
+	return qb
+}
+
+// OrderBy добавляет сортировку. columns - белый список полей сортировки.
+// Поле, которого нет в списке, делает запрос ошибочным.
+func (qb *QueryBuilder) OrderBy(fields []model.OrderInfo, columns ListFields) *QueryBuilder {
+	clause, err := OrderClause(fields, columns)
+	if err != nil {
+		qb.fail(err)
+		return qb
+	}
+	if clause != "" {
+		qb.orderBy = append(qb.orderBy, clause)
+	}
+	return qb
+}
+
+// Page добавляет LIMIT и OFFSET из параметров страницы
+func (qb *QueryBuilder) Page(pager model.PageInfo) *QueryBuilder {
+	if pager.Limit != nil {
+		qb.limit = qb.Param(*pager.Limit)
+	}
+	if pager.Offset != nil {
+		qb.offset = qb.Param(*pager.Offset)
+	}
+	return qb
 }
 
-func AddInClause(srcquery string, values []string, field string, keyword string) (string) {
-	svalues := Map(values, func(p string) string {
-        return `'` + p + `'`
-    })
-	return fmt.Sprintf("%s %s (\"%s\" IN (%s)) ", srcquery, keyword, field, strings.Join(svalues, ",")) 
+// Args возвращает накопленные аргументы, когда текст запроса собирается по шаблону через Param и Params
+func (qb *QueryBuilder) Args() []any {
+	return qb.args
 }
 
-func GetInClauseItemsString(values []string) (string) {
-	svalues := Map(values, func(p string) string {
-        return `'` + p + `'`
-    })
-	return strings.Join(svalues, ",") 
+// Conditions возвращает условия WHERE без ключевого слова и аргументы, например для gorm Where
+func (qb *QueryBuilder) Conditions() (string, []any, error) {
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+	return strings.Join(qb.where, " AND "), qb.args, nil
 }
 
-func AddOrderByClause(srcquery string, fields []model.OrderInfo) (string) {
-	svalues := Map(fields, func(p model.OrderInfo) string {
-		clause := `"` + p.Field + `"`
-		if p.Desc {
-			clause = clause + ` DESC` 
+// Build возвращает текст запроса и аргументы
+func (qb *QueryBuilder) Build() (string, []any, error) {
+	if qb.err != nil {
+		return "", nil, qb.err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(qb.base)
+
+	if len(qb.where) > 0 {
+		sb.WriteString(" WHERE ")
+		sb.WriteString(strings.Join(qb.where, " AND "))
+	}
+
+	if len(qb.groupBy) > 0 {
+		sb.WriteString(" GROUP BY ")
+		sb.WriteString(strings.Join(qb.groupBy, ","))
+	}
+
+	if len(qb.orderBy) > 0 {
+		sb.WriteString(" ORDER BY ")
+		sb.WriteString(strings.Join(qb.orderBy, ","))
+	}
+
+	if qb.limit != "" {
+		sb.WriteString(" LIMIT ")
+		sb.WriteString(qb.limit)
+	}
+
+	if qb.offset != "" {
+		sb.WriteString(" OFFSET ")
+		sb.WriteString(qb.offset)
+	}
+
+	return sb.String(), qb.args, nil
+}
+
+func (qb *QueryBuilder) fail(err error) {
+	if qb.err == nil {
+		qb.err = err
+	}
+}
+
+// OrderClause возвращает выражение ORDER BY без ключевого слова, проверяя поля по белому списку columns
+func OrderClause(fields []model.OrderInfo, columns ListFields) (string, error) {
+	clauses := make([]string, 0, len(fields))
+	for _, field := range fields {
+		listField, ok := columns[field.Field]
+		if !ok {
+			return "", fmt.Errorf("сортировка по полю %q не поддерживается", field.Field)
+		}
+		column := listField.Column
+		if field.Desc {
+			column += " DESC"
 		}
-        return clause
-    })
-	return fmt.Sprintf("%s ORDER BY %s ", srcquery, strings.Join(svalues, ",")) 
-}
-
-func AddGroupClause(srcquery string, fields []string) (string) {
-	svalues := Map(fields, func(p string) string {
-		return `"` + p + `"`
-    })
-	return fmt.Sprintf("%s GROUP BY %s ", srcquery, strings.Join(svalues, ",")) 
-}
-
-func AddPaginationClause(srcquery string, pager model.PageInfo) (string, []interface{}) {
-	var args []interface{}
-	dstquery := srcquery
-    paramCount := 0
-    
-    if pager.Limit != nil {
-        paramCount++
-        dstquery += fmt.Sprintf(" LIMIT $%d", paramCount)
-        args = append(args, *pager.Limit)
-    }
-    
-    if pager.Offset != nil {
-        paramCount++
-        dstquery += fmt.Sprintf(" OFFSET $%d", paramCount)
-        args = append(args, *pager.Offset)
-    }
-
-	return dstquery, args
-}
\ No newline at end of file
+		clauses = append(clauses, column)
+	}
+	return strings.Join(clauses, ","), nil
+}
+
+// QuoteIdent заключает идентификатор в двойные кавычки с экранированием
+func QuoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Args приводит список значений к списку аргументов запроса
+func Args[T any](values []T) []any {
+	return Map(values, func(p T) any {
+		return p
+	})
+}
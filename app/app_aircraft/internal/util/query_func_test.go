@@ -0,0 +1,81 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestQueryBuilder тестирует построение запросов с плейсхолдерами
+func TestQueryBuilder(t *testing.T) {
+	orderColumns := ListFields{"code": {Column: `"Code"`}, "range": {Column: `"range"`, Type: FieldNumber}}
+
+	tests := []struct {
+		name   string
+		build  func() *QueryBuilder
+		query  string
+		args   []any
+		failed bool
+	}{
+		{"Query_WhereIn", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").
+				WhereIn("aircraft_code", Args([]string{"319", "x' or '1'='1"})).
+				GroupBy("aircraft_code").
+				OrderBy([]model.OrderInfo{{Field: "code"}, {Field: "range", Desc: true}}, orderColumns)
+		}, `select * from t WHERE ("aircraft_code" IN ($1,$2)) GROUP BY "aircraft_code" ORDER BY "Code","range" DESC`,
+			[]any{"319", "x' or '1'='1"}, false},
+		{"Query_EmptyIn", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").WhereIn("code", nil)
+		}, `select * from t WHERE ("code" IN (NULL))`, nil, false},
+		{"Query_WherePage", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").
+				WhereEq("code", "319").
+				Where("range >= ? and range < ?", 1000, 5000).
+				Page(model.PageInfo{Limit: Ptr(10), Offset: Ptr(20)})
+		}, `select * from t WHERE ("code" = $1) AND (range >= $2 and range < $3) LIMIT $4 OFFSET $5`,
+			[]any{"319", 1000, 5000, 10, 20}, false},
+		{"Query_Question", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").
+				Placeholders(PlaceholderQuestion).
+				WhereIn("code", Args([]string{"A", "B"})).
+				Page(model.PageInfo{Limit: Ptr(5)})
+		}, `select * from t WHERE ("code" IN (?,?)) LIMIT ?`, []any{"A", "B", 5}, false},
+		{"Query_QuoteIdent", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").WhereEq(`co"de`, 1)
+		}, `select * from t WHERE ("co""de" = $1)`, []any{1}, false},
+		{"Query_UnknownOrder", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").
+				OrderBy([]model.OrderInfo{{Field: "range; drop table t"}}, orderColumns)
+		}, "", nil, true},
+		{"Query_WhereArgsMismatch", func() *QueryBuilder {
+			return NewQueryBuilder("select * from t").Where("a = ? and b = ?", 1)
+		}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			query, args, err := tt.build().Build()
+
+			if tt.failed {
+				if err == nil {
+					t.Errorf("Ожидалась ошибка построения запроса, получено '%s'", query)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Ошибка построения запроса: %v", err)
+			}
+
+			if query != tt.query {
+				t.Errorf("Ожидался запрос '%s', получено '%s'", tt.query, query)
+			}
+
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("Ожидались аргументы %v, получено %v", tt.args, args)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package util
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Транслитерация кириллицы в латиницу для сравнения строк на разных языках
+var translitRu = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Оценки совпадения, от точного к нечеткому
+const (
+	scoreExact     = 100
+	scorePrefix    = 90
+	scoreWord      = 80
+	scoreSubstring = 60
+	scoreFuzzy     = 50
+)
+
+// Поле кандидата поиска
+type SearchField struct {
+	Name  string
+	Value string
+}
+
+// Кандидат поиска: ключ (код) и поля для сравнения
+type SearchCandidate struct {
+	Key    string
+	Fields []SearchField
+}
+
+// Результат ранжирования кандидата
+type SearchRank struct {
+	Key     string
+	Score   float64
+	Matches []model.SearchMatch
+}
+
+// Translit возвращает строку в нижнем регистре латиницей
+func Translit(value string) string {
+	key, _ := searchKey(value)
+	return string(key)
+}
+
+// RankSearch сравнивает строку поиска с полями кандидатов без учета регистра и алфавита:
+// обе стороны транслитерируются в латиницу, поэтому "moskva" находит "Москва".
+// Учитываются совпадение целиком, префикс поля, префикс слова, подстрока и опечатки в префиксе слова.
+// Результаты упорядочены по убыванию оценки, совпадения указывают позиции в исходных значениях полей.
+func RankSearch(query string, candidates []SearchCandidate) []SearchRank {
+	q, _ := searchKey(strings.TrimSpace(query))
+	if len(q) == 0 {
+		return nil
+	}
+
+	var ranks []SearchRank
+	for _, candidate := range candidates {
+		rank := SearchRank{Key: candidate.Key}
+		for _, field := range candidate.Fields {
+			score, start, end := matchField(q, field.Value)
+			if score == 0 {
+				continue
+			}
+			rank.Matches = append(rank.Matches, model.SearchMatch{Field: field.Name, Start: start, End: end})
+			if float64(score) > rank.Score {
+				rank.Score = float64(score)
+			}
+		}
+		if rank.Score > 0 {
+			// Совпадение по нескольким полям немного поднимает кандидата
+			rank.Score += float64(len(rank.Matches)-1) * 0.5
+			ranks = append(ranks, rank)
+		}
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Score != ranks[j].Score {
+			return ranks[i].Score > ranks[j].Score
+		}
+		return ranks[i].Key < ranks[j].Key
+	})
+
+	return ranks
+}
+
+// matchField возвращает оценку совпадения и позицию найденного фрагмента в символах значения
+func matchField(q []rune, value string) (int, int, int) {
+	key, src := searchKey(value)
+	if len(key) == 0 {
+		return 0, 0, 0
+	}
+
+	span := func(start int, end int) (int, int) {
+		return src[start], src[end-1] + 1
+	}
+
+	text := string(key)
+	query := string(q)
+
+	if text == query {
+		start, end := span(0, len(key))
+		return scoreExact, start, end
+	}
+
+	if strings.HasPrefix(text, query) {
+		start, end := span(0, len(q))
+		return scorePrefix, start, end
+	}
+
+	words := searchWords(key)
+	for _, word := range words {
+		if hasRunePrefix(key[word:], q) {
+			start, end := span(word, word+len(q))
+			return scoreWord, start, end
+		}
+	}
+
+	if index := strings.Index(text, query); index >= 0 {
+		at := len([]rune(text[:index]))
+		start, end := span(at, at+len(q))
+		return scoreSubstring, start, end
+	}
+
+	// Опечатки ищутся в префиксах слов, допустимое число правок зависит от длины запроса
+	maxDistance := fuzzyDistance(len(q))
+	if maxDistance == 0 {
+		return 0, 0, 0
+	}
+
+	best, bestStart, bestEnd := maxDistance+1, 0, 0
+	for _, word := range words {
+		for length := len(q) - maxDistance; length <= len(q)+maxDistance; length++ {
+			if length <= 0 || word+length > len(key) {
+				continue
+			}
+			distance := levenshtein(q, key[word:word+length])
+			if distance < best {
+				best, bestStart, bestEnd = distance, word, word+length
+			}
+		}
+	}
+
+	if best > maxDistance {
+		return 0, 0, 0
+	}
+
+	start, end := span(bestStart, bestEnd)
+	return scoreFuzzy - 10*best, start, end
+}
+
+// searchKey приводит строку к нижнему регистру латиницей.
+// Для каждого символа ключа возвращается индекс исходного символа для подсветки совпадений.
+func searchKey(value string) ([]rune, []int) {
+	var key []rune
+	var src []int
+
+	for i, r := range []rune(value) {
+		r = unicode.ToLower(r)
+		if latin, ok := translitRu[r]; ok {
+			for _, l := range latin {
+				key = append(key, l)
+				src = append(src, i)
+			}
+			continue
+		}
+		key = append(key, r)
+		src = append(src, i)
+	}
+
+	return key, src
+}
+
+// searchWords возвращает индексы начала слов ключа
+func searchWords(key []rune) []int {
+	var words []int
+	for i, r := range key {
+		if !isWordRune(r) {
+			continue
+		}
+		if i == 0 || !isWordRune(key[i-1]) {
+			words = append(words, i)
+		}
+	}
+	return words
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func hasRunePrefix(value []rune, prefix []rune) bool {
+	if len(value) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if value[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyDistance - допустимое число опечаток для запроса длины n
+func fuzzyDistance(n int) int {
+	switch {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// levenshtein возвращает расстояние редактирования между строками
+func levenshtein(a []rune, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// Codes ограничивает запрос кодами, найденными поиском. Без поиска (nil) условие не добавляется.
+func (qb *QueryBuilder) Codes(column string, codes []string) *QueryBuilder {
+	if codes == nil {
+		return qb
+	}
+	return qb.WhereIn(column, Args(codes))
+}
@@ -0,0 +1,104 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestTranslit тестирует транслитерацию кириллицы в латиницу
+func TestTranslit(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"Москва", "moskva"},
+		{"Шереметьево", "sheremetevo"},
+		{"Хабаровск", "khabarovsk"},
+		{"Южно-Сахалинск", "yuzhno-sakhalinsk"},
+		{"Boeing 777-300", "boeing 777-300"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if value := Translit(tt.value); value != tt.expected {
+				t.Errorf("Ожидалось '%s', получено '%s'", tt.expected, value)
+			}
+		})
+	}
+}
+
+// TestRankSearch тестирует ранжирование и подсветку найденных фрагментов
+func TestRankSearch(t *testing.T) {
+	candidates := []SearchCandidate{
+		{Key: "SVO", Fields: []SearchField{{"code", "SVO"}, {"nameRu", "Шереметьево"}, {"cityRu", "Москва"}, {"cityEn", "Moscow"}}},
+		{Key: "DME", Fields: []SearchField{{"code", "DME"}, {"nameRu", "Домодедово"}, {"cityRu", "Москва"}, {"cityEn", "Moscow"}}},
+		{Key: "KHV", Fields: []SearchField{{"code", "KHV"}, {"nameRu", "Хабаровск-Новый"}, {"cityRu", "Хабаровск"}, {"cityEn", "Khabarovsk"}}},
+		{Key: "773", Fields: []SearchField{{"code", "773"}, {"nameEn", "Boeing 777-300"}}},
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		keys    []string
+		matches []model.SearchMatch
+	}{
+		{"Search_Translit", "moskva", []string{"DME", "SVO"},
+			[]model.SearchMatch{{Field: "cityRu", Start: 0, End: 6}}},
+		{"Search_CyrillicPrefix", "хаб", []string{"KHV"},
+			[]model.SearchMatch{{Field: "nameRu", Start: 0, End: 3}, {Field: "cityRu", Start: 0, End: 3}, {Field: "cityEn", Start: 0, End: 4}}},
+		{"Search_Code", "svo", []string{"SVO"},
+			[]model.SearchMatch{{Field: "code", Start: 0, End: 3}}},
+		{"Search_WordPrefix", "777", []string{"773"},
+			[]model.SearchMatch{{Field: "nameEn", Start: 7, End: 10}}},
+		{"Search_Fuzzy", "domadedovo", []string{"DME"},
+			[]model.SearchMatch{{Field: "nameRu", Start: 0, End: 10}}},
+		{"Search_ShortNoFuzzy", "svx", nil, nil},
+		{"Search_Empty", "  ", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			ranks := RankSearch(tt.query, candidates)
+
+			var keys []string
+			for _, rank := range ranks {
+				keys = append(keys, rank.Key)
+			}
+
+			if !reflect.DeepEqual(keys, tt.keys) {
+				t.Fatalf("Ожидались ключи %v, получено %v", tt.keys, keys)
+			}
+
+			if len(ranks) > 0 && !reflect.DeepEqual(ranks[0].Matches, tt.matches) {
+				t.Errorf("Ожидались совпадения %+v, получено %+v", tt.matches, ranks[0].Matches)
+			}
+		})
+	}
+}
+
+// TestRankSearchOrder тестирует порядок результатов по виду совпадения
+func TestRankSearchOrder(t *testing.T) {
+	candidates := []SearchCandidate{
+		{Key: "substring", Fields: []SearchField{{"name", "Лондон"}}},
+		{Key: "word", Fields: []SearchField{{"name", "Новый Дон"}}},
+		{Key: "prefix", Fields: []SearchField{{"name", "Донецк"}}},
+		{Key: "exact", Fields: []SearchField{{"name", "Дон"}}},
+		{Key: "fuzzy", Fields: []SearchField{{"name", "Тон"}}},
+	}
+
+	ranks := RankSearch("don", candidates)
+
+	var keys []string
+	for _, rank := range ranks {
+		keys = append(keys, rank.Key)
+	}
+
+	// Опечатки для коротких запросов не допускаются
+	expected := []string{"exact", "prefix", "word", "substring"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Errorf("Ожидался порядок %v, получено %v", expected, keys)
+	}
+}
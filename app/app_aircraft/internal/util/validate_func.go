@@ -0,0 +1,116 @@
+package util
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// Форматы кодов справочников и номера места
+var (
+	aircraftCodeFormat = regexp.MustCompile(`^[0-9A-Z]{3}$`)
+	airportCodeFormat  = regexp.MustCompile(`^[A-Z]{3}$`)
+	seatNoFormat       = regexp.MustCompile(`^[1-9][0-9]{0,2}[A-Z]$`)
+)
+
+// Проверка входных данных создается один раз: разбор тегов структур кэшируется
+var inputValidator = sync.OnceValue(newInputValidator)
+
+// ValidateInput проверяет входные данные по правилам тегов validate и возвращает все нарушения.
+// Свойство сообщения валидации - имя поля JSON, для названий на других языках - names[<язык>].
+func ValidateInput(input any) []model.Validation {
+	err := inputValidator().Struct(input)
+
+	var fieldErrors validator.ValidationErrors
+	if !errors.As(err, &fieldErrors) {
+		if err != nil {
+			return []model.Validation{i18n.Validation("", i18n.MsgFieldInvalid, err)}
+		}
+		return nil
+	}
+
+	return Map(fieldErrors, func(p validator.FieldError) model.Validation {
+		key, args := fieldMessage(p)
+		return i18n.Validation(p.Field(), key, args...)
+	})
+}
+
+func newInputValidator() *validator.Validate {
+	validate := validator.New(validator.WithRequiredStructEnabled())
+
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			return field.Name
+		}
+		return name
+	})
+
+	rules := map[string]func(string) bool{
+		"notblank": func(value string) bool {
+			return strings.TrimSpace(value) != ""
+		},
+		"aircraft_code": aircraftCodeFormat.MatchString,
+		"airport_code":  airportCodeFormat.MatchString,
+		"seat_no":       seatNoFormat.MatchString,
+		"lang_tag":      languageTag.MatchString,
+		"iana_tz":       isTimezone,
+	}
+	for tag, rule := range rules {
+		err := validate.RegisterValidation(tag, func(field validator.FieldLevel) bool {
+			return rule(field.Field().String())
+		})
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	return validate
+}
+
+// isTimezone проверяет имя часового пояса по базе IANA, например Europe/Moscow
+func isTimezone(value string) bool {
+	if value == "" || value == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(value)
+	return err == nil
+}
+
+// fieldMessage возвращает ключ сообщения каталога и параметры для нарушенного правила
+func fieldMessage(field validator.FieldError) (string, []any) {
+	switch field.Tag() {
+	case "required", "notblank":
+		return i18n.MsgFieldRequired, nil
+	case "aircraft_code":
+		return i18n.MsgFieldAircraftCode, []any{field.Value()}
+	case "airport_code":
+		return i18n.MsgFieldAirportCode, []any{field.Value()}
+	case "seat_no":
+		return i18n.MsgFieldSeatNo, []any{field.Value()}
+	case "lang_tag":
+		return i18n.MsgRequestLang, []any{field.Value()}
+	case "iana_tz":
+		return i18n.MsgFieldTimezone, []any{field.Value()}
+	case "oneof":
+		return i18n.MsgFieldOneOf, []any{strings.ReplaceAll(field.Param(), " ", ", "), field.Value()}
+	case "gt":
+		return i18n.MsgFieldGreater, []any{field.Param(), field.Value()}
+	case "gte":
+		return i18n.MsgFieldMin, []any{field.Param(), field.Value()}
+	case "lte":
+		return i18n.MsgFieldMax, []any{field.Param(), field.Value()}
+	case "max":
+		return i18n.MsgFieldLength, []any{field.Param()}
+	default:
+		return i18n.MsgFieldInvalid, []any{field.Value()}
+	}
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/model"
+)
+
+// TestValidateInput тестирует правила проверки входных данных самолетов, аэропортов и мест
+func TestValidateInput(t *testing.T) {
+	aircraft := model.AircraftInput{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300", Range: 11100}
+	airport := model.AirportInput{Code: "SVO", NameRu: "Шереметьево", NameEn: "Sheremetyevo International Airport",
+		CityRu: "Москва", CityEn: "Moscow", Latitude: 55.972, Longitude: 37.414, Timezone: "Europe/Moscow"}
+
+	tests := []struct {
+		name       string
+		input      any
+		properties []string
+		codes      []string
+	}{
+		{"Aircraft_Valid", aircraft, nil, nil},
+		{"Aircraft_Invalid",
+			model.AircraftInput{Code: "7730000000", NameRu: " ", Names: map[string]string{"1x": ""}, Range: -1},
+			[]string{"code", "nameRu", "nameEn", "names[1x]", "names[1x]", "range"},
+			[]string{i18n.MsgFieldAircraftCode, i18n.MsgFieldRequired, i18n.MsgFieldRequired, i18n.MsgRequestLang, i18n.MsgFieldRequired, i18n.MsgFieldGreater}},
+		{"Airport_Valid", airport, nil, nil},
+		{"Airport_Invalid",
+			model.AirportInput{Code: "svo", NameRu: "Шереметьево", NameEn: "Sheremetyevo", CityRu: "Москва", CityEn: "Moscow",
+				Latitude: 91, Longitude: -181, Timezone: "Moscow"},
+			[]string{"code", "latitude", "longitude", "timezone"},
+			[]string{i18n.MsgFieldAirportCode, i18n.MsgFieldMax, i18n.MsgFieldMin, i18n.MsgFieldTimezone}},
+		{"Airport_LocalTimezone",
+			func() model.AirportInput { p := airport; p.Timezone = "Local"; return p }(),
+			[]string{"timezone"}, []string{i18n.MsgFieldTimezone}},
+		{"Seat_Valid", model.SeatInput{Code: "SU9", SeatType: "Economy", SeatNumb: "12A"}, nil, nil},
+		{"Seat_Invalid", model.SeatInput{Code: "", SeatType: "First", SeatNumb: "A12"},
+			[]string{"code", "seatType", "seatNumb"},
+			[]string{i18n.MsgFieldAircraftCode, i18n.MsgFieldOneOf, i18n.MsgFieldSeatNo}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+
+			validations := ValidateInput(tt.input)
+
+			var properties, codes []string
+			for _, validation := range validations {
+				properties = append(properties, validation.Property)
+				codes = append(codes, validation.Code)
+			}
+
+			if !reflect.DeepEqual(properties, tt.properties) || !reflect.DeepEqual(codes, tt.codes) {
+				t.Errorf("Ожидались %v %v, получено %+v", tt.properties, tt.codes, validations)
+			}
+		})
+	}
+}
@@ -11,20 +11,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"html"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"github.com/snpavlov/app_aircraft/internal/conf"
+	"github.com/snpavlov/app_aircraft/internal/domain"
+	"github.com/snpavlov/app_aircraft/internal/i18n"
+	"github.com/snpavlov/app_aircraft/internal/geo"
+	"github.com/snpavlov/app_aircraft/internal/repo"
 	"github.com/snpavlov/app_aircraft/internal/service"
 	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/util"
 )
 
 func main() {
@@ -46,25 +59,77 @@ func main() {
 
 		v1.POST("/aircrafts/create", server.createAircraft)
 		v1.POST("/aircrafts/update", server.updateAircraft)
+		v1.PATCH("/aircrafts/:code", server.patchAircraft)
 		v1.POST("/aircrafts/delete/:code", server.deleteAircraft)
 		v1.DELETE("/aircrafts/:code", server.deleteAircraft)
 
+		v1.GET("/aircrafts/:code/seats", server.getAircraftSeats)
+		v1.POST("/aircrafts/:code/seats", server.createSeat)
+		v1.PUT("/aircrafts/:code/seats/:seat", server.updateSeat)
+		v1.DELETE("/aircrafts/:code/seats/:seat", server.deleteSeat)
+		v1.PUT("/aircrafts/:code/layout", server.replaceAircraftLayout)
+
 		v1.GET("/airports", server.getAirports)
+		v1.GET("/airports/nearby", server.getNearbyAirports)
 		v1.GET("/airports/:code", server.getAirportByCode)
+
+		v1.POST("/airports/create", server.createAirport)
+		v1.POST("/airports/update", server.updateAirport)
+		v1.PATCH("/airports/:code", server.patchAirport)
+		v1.POST("/airports/delete/:code", server.deleteAirport)
+		v1.DELETE("/airports/:code", server.deleteAirport)
+
+		v1.GET("/flights", server.getFlights)
+		v1.GET("/flights/:id", server.getFlightById)
+		v1.GET("/routes", server.getRoutes)
+
+		v1.GET("/search", server.search)
 	}
 
 	startinfo(*server.addr);
 
-	router.Run(*server.addr)
+	httpServer := &http.Server{
+		Addr:    *server.addr,
+		Handler: router,
+	}
+
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Ошибка запуска сервера: %v", err)
+		}
+	}()
+
+	// Ожидание сигнала остановки
+	stopCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-stopCtx.Done()
 
+	log.Println("Остановка сервера...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Ошибка остановки сервера: %v", err)
+	}
+
+	server.Close()
 }
 
+// Время ожидания завершения активных запросов при остановке
+const shutdownTimeout = 10 * time.Second
+
 
 type AppServer struct {
     greeting *string
 	addr *string
 	aircraftService service.IAircraftService
 	airportService service.IAirportService
+	flightService service.IFlightService
+	searchService service.ISearchService
+	config conf.IConfiguration
+	requireIfMatch bool
+	closers []io.Closer
 }
 
 func usage() {
@@ -96,12 +161,18 @@ func (server AppServer) InitConfiguration() (config conf.IConfiguration) {
 func (server AppServer) Initialize() (AppServer) {
 
 	config := server.InitConfiguration()
+	server.config = config
 	svraddr, err := config.GetServerAddress()
 	if err != nil {
 		log.Fatalf("Не удалось получить адрес сервера из конфигурации!")
 		os.Exit(3)
 	}
 
+	server.requireIfMatch, err = config.GetRequireIfMatch()
+	if err != nil {
+		log.Fatalf("Не удалось получить параметр require_if_match из конфигурации: %v", err)
+	}
+
 	server.greeting = flag.String("g", "Hello", "Greet with `greeting`")
 	server.addr     = flag.String("addr", svraddr, "address to serve")	
 
@@ -115,30 +186,48 @@ func (server AppServer) Initialize() (AppServer) {
 		usage()
 	}
 
-	// Подготка функционального сервиса самолетов
-	aircraftService, err := service.AircraftService{}.NewAircraftService(config)
+	// Общие пулы соединений открываются один раз при запуске
+	aircraftRepo, err := repo.AircraftSqlRepo{}.NewAircraftSqlRepo(config)
 
 	if err != nil {
-		log.Fatalf("Ошибка инициализации сервиса 'AircraftService': %v", err)
+		log.Fatalf("Ошибка инициализации репозитория 'AircraftSqlRepo': %v", err)
 		os.Exit(1)
 	}
 
-	server.aircraftService = aircraftService
-
-	// Подготка функционального сервиса аэропортов
-	airportService, err := service. AirportService{}.NewAirportService(config)
+	gormContext, err := repo.GormDBContext{}.NewGormDBContext(config)
 
 	if err != nil {
-		log.Fatalf("Ошибка инициализации сервиса 'AirportService': %v", err)
+		log.Fatalf("Ошибка инициализации репозитория 'GormDBContext': %v", err)
 		os.Exit(1)
 	}
 
-	server.airportService = airportService	
+	server.closers = []io.Closer{aircraftRepo, gormContext}
+
+	// Подготка функционального сервиса самолетов
+	server.aircraftService = service.AircraftService{Repo: aircraftRepo}
+
+	// Подготка функционального сервиса аэропортов
+	server.airportService = service.AirportService{Repo: gormContext}
+
+	// Подготка функционального сервиса рейсов
+	server.flightService = service.FlightService{Repo: gormContext}
+
+	// Подготка сервиса поиска самолетов и аэропортов
+	server.searchService = service.SearchService{AircraftRepo: aircraftRepo, AirportRepo: gormContext}
 
 	return server
 
 }
 
+// Close закрывает пулы соединений с базой данных
+func (server AppServer) Close() {
+	for _, closer := range server.closers {
+		if err := closer.Close(); err != nil {
+			log.Printf("Ошибка закрытия подключения к базе данных: %v", err)
+		}
+	}
+}
+
 func (server AppServer) greet(ctx *gin.Context) {
 	ctx.Header("Content-Type", "text/html")
 
@@ -173,29 +262,38 @@ func (server AppServer) getAircafts(ctx *gin.Context) {
 
 	err := ctx.ShouldBindQuery(&pager)
 	if err != nil {
-		argres := model.ServiceListResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка чтения аргументов запроса",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, argres)
+		writeListError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	var query model.ListQuery
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		writeListError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	chain, localize, err := responseLanguages(ctx)
+	if err != nil {
+		writeListError[model.AircraftData](ctx, err)
 		return
 	}
 
 	// Call the data method
-	result, err := server.aircraftService.GetAircrafts(pager)
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.GetAircrafts(queryCtx, pager, query)
 
 	if err != nil {
-		result = model.ServiceListResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeListError[model.AircraftData](ctx, err)
+		return
+	}
+
+	if localize {
+		ctx.IndentedJSON(http.StatusOK, service.LocalizeList(result, func(p model.AircraftData) model.LocalizedAircraftData {
+			return service.LocalizeAircraft(p, chain)
+		}))
 		return
 	}
 
@@ -207,60 +305,62 @@ func (server AppServer) getAircaftByCode(ctx *gin.Context) {
 	code := ctx.Param("code")
 
 	if len(code) == 0 {
-		argres := model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка получения шифра. Аргумент 'code' не задан",
-		}
-		ctx.IndentedJSON(500, argres)
+		writeDataError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
 		return
 	}	
 
+	chain, localize, err := responseLanguages(ctx)
+	if err != nil {
+		writeDataError[model.AircraftData](ctx, err)
+		return
+	}
+
 	// Call the data method
-	result, err := server.aircraftService.GetAircraftByCode(code)
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.GetAircraftByCode(queryCtx, code)
 
 	if err != nil {
-		result = model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeDataError[model.AircraftData](ctx, err)
 		return
 	}
 
-	ctx.IndentedJSON(http.StatusOK, result)	
+	var body any = result
+	if localize {
+		body = service.LocalizeData(result, func(p model.AircraftData) model.LocalizedAircraftData {
+			return service.LocalizeAircraft(p, chain)
+		})
+	}
+
+	if writeETag(ctx, result.Data.Version, body) {
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, body)	
 }
 
 func (server AppServer) createAircraft(ctx *gin.Context) {
 	
 	var input model.AircraftInput
 
-	if err := ctx.BindJSON(&input); err != nil {
-		argres := model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: fmt.Sprintf("Ошибка получения данных: %v", err.Error()),
-		}
-		ctx.IndentedJSON(http.StatusBadRequest, argres)
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
 		return
 	}
 
 	// Call the data method
-	result, err := server.aircraftService.CreateAircraft(input)
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.CreateAircraft(queryCtx, input)
 
 	if err != nil {
-		result = model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeDataError[model.AircraftData](ctx, err)
 		return
 	}
 
+	ctx.Header("ETag", util.ETag(result.Data.Version))
 	ctx.IndentedJSON(http.StatusOK, result)	
 }
 
@@ -268,30 +368,67 @@ func (server AppServer) updateAircraft(ctx *gin.Context) {
 	
 	var input model.AircraftInput
 
-	if err := ctx.BindJSON(&input); err != nil {
-		argres := model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: fmt.Sprintf("Ошибка получения данных: %v", err.Error()),
-		}
-		ctx.IndentedJSON(http.StatusBadRequest, argres)
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	ifMatch, err := server.ifMatch(ctx, input.Code)
+	if err != nil {
+		writeDataError[model.AircraftData](ctx, err)
 		return
 	}
 
 	// Call the data method
-	result, err := server.aircraftService.UpdateAircraft(input)
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.UpdateAircraft(queryCtx, input, ifMatch)
 
 	if err != nil {
-		result = model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeDataError[model.AircraftData](ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", util.ETag(result.Data.Version))
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+// patchAircraft изменяет поля из документа JSON Merge Patch (RFC 7396), тело запроса
+// с типом application/merge-patch+json или application/json
+func (server AppServer) patchAircraft(ctx *gin.Context) {
+	
+	code := ctx.Param("code")
+
+	if len(code) == 0 {
+		writeDataError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
+		return
+	}	
+
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		writeDataError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	ifMatch, err := server.ifMatch(ctx, code)
+	if err != nil {
+		writeDataError[model.AircraftData](ctx, err)
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.PatchAircraft(queryCtx, code, patch, ifMatch)
+
+	if err != nil {
+		writeDataError[model.AircraftData](ctx, err)
 		return
 	}
 
+	ctx.Header("ETag", util.ETag(result.Data.Version))
 	ctx.IndentedJSON(http.StatusOK, result)	
 }
 
@@ -300,32 +437,158 @@ func (server AppServer) deleteAircraft(ctx *gin.Context) {
 	code := ctx.Param("code")
 
 	if len(code) == 0 {
-		argres := model.ServiceDataResult[model.AircraftData]{
-			Result: false, 
-			Message: "Ошибка получения шифра. Аргумент 'code' не задан",
-		}
-		ctx.IndentedJSON(500, argres)
+		writeDataError[model.AircraftData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
 		return
 	}	
 
+	var input model.DeleteAircraftInput
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		writeDataError[string](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	ifMatch, err := server.ifMatch(ctx, code)
+	if err != nil {
+		writeDataError[string](ctx, err)
+		return
+	}
+
 	// Call the data method
-	result, err := server.aircraftService.DeleteAircraft(code)
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.DeleteAircraft(queryCtx, code, input, ifMatch)
 
 	if err != nil {
-		result = model.ServiceDataResult[string]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeDataError[string](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) getAircraftSeats(ctx *gin.Context) {
+	
+	code := ctx.Param("code")
+
+	if len(code) == 0 {
+		writeDataError[model.AircraftSeatsData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
+		return
+	}	
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.GetAircraftSeats(queryCtx, code)
+
+	if err != nil {
+		writeDataError[model.AircraftSeatsData](ctx, err)
 		return
 	}
 
 	ctx.IndentedJSON(http.StatusOK, result)	
 }
 
+func (server AppServer) createSeat(ctx *gin.Context) {
+	
+	var input model.SeatInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AircraftSeatsData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	// Код самолета задается маршрутом
+	input.Code = ctx.Param("code")
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.CreateSeat(queryCtx, input)
+
+	if err != nil {
+		writeDataError[model.AircraftSeatsData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) updateSeat(ctx *gin.Context) {
+	
+	var input model.SeatInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AircraftSeatsData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	// Код самолета и номер места задаются маршрутом
+	input.Code = ctx.Param("code")
+	input.SeatNumb = ctx.Param("seat")
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.UpdateSeat(queryCtx, input)
+
+	if err != nil {
+		writeDataError[model.AircraftSeatsData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) deleteSeat(ctx *gin.Context) {
+	
+	code := ctx.Param("code")
+	seatNo := ctx.Param("seat")
+
+	if len(code) == 0 || len(seatNo) == 0 {
+		writeDataError[model.AircraftSeatsData](ctx, domain.NewArgumentError(i18n.MsgRequestSeat, nil))
+		return
+	}	
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.DeleteSeat(queryCtx, code, seatNo)
+
+	if err != nil {
+		writeDataError[model.AircraftSeatsData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) replaceAircraftLayout(ctx *gin.Context) {
+	
+	var input model.LayoutInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AircraftLayoutData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "aircrafts")
+	defer cancel()
+
+	result, err := server.aircraftService.ReplaceAircraftLayout(queryCtx, ctx.Param("code"), input)
+
+	if err != nil {
+		writeDataError[model.AircraftLayoutData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
 
 func (server AppServer) getAirports(ctx *gin.Context) {
 
@@ -336,29 +599,108 @@ func (server AppServer) getAirports(ctx *gin.Context) {
 
 	err := ctx.ShouldBindQuery(&pager)
 	if err != nil {
-		argres := model.ServiceListResult[model.AirportData]{
-			Result: false, 
-			Message: "Ошибка чтения аргументов запроса",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, argres)
+		writeListError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	var query model.ListQuery
+	err = ctx.ShouldBindQuery(&query)
+	if err != nil {
+		writeListError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	format, err := responseFormat(ctx, formatJSON)
+	if err != nil {
+		writeListError[model.AirportData](ctx, err)
+		return
+	}
+
+	chain, localize, err := responseLanguages(ctx)
+	if err != nil {
+		writeListError[model.AirportData](ctx, err)
 		return
 	}
 
 	// Call the data method
-	result, err := server.airportService.GetAirports(pager)
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.GetAirports(queryCtx, pager, query)
 
 	if err != nil {
-		result = model.ServiceListResult[model.AirportData]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeListError[model.AirportData](ctx, err)
+		return
+	}
+
+	if format == formatGeoJSON {
+		writeGeoJSON(ctx, service.AirportFeatures(*result.Items))
+		return
+	}
+
+	if localize {
+		ctx.IndentedJSON(http.StatusOK, service.LocalizeList(result, func(p model.AirportData) model.LocalizedAirportData {
+			return service.LocalizeAirport(p, chain)
+		}))
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)
+}
+
+func (server AppServer) getNearbyAirports(ctx *gin.Context) {
+
+	var input model.GeoInput
+
+	err := ctx.ShouldBindQuery(&input)
+	if err != nil {
+		writeListError[model.AirportGeoData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.GetNearbyAirports(queryCtx, input)
+
+	if err != nil {
+		writeListError[model.AirportGeoData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)
+}
+
+func (server AppServer) getRoutes(ctx *gin.Context) {
+
+	var filter model.RouteFilter
+
+	err := ctx.ShouldBindQuery(&filter)
+	if err != nil {
+		writeListError[model.RouteData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	format, err := responseFormat(ctx, formatGeoJSON)
+	if err != nil {
+		writeListError[model.RouteData](ctx, err)
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "flights")
+	defer cancel()
+
+	result, err := server.flightService.GetRoutes(queryCtx, filter)
+
+	if err != nil {
+		writeListError[model.RouteData](ctx, err)
+		return
+	}
+
+	if format == formatGeoJSON {
+		writeGeoJSON(ctx, service.RouteFeatures(*result.Items))
 		return
 	}
 
@@ -370,31 +712,471 @@ func (server AppServer) getAirportByCode(ctx *gin.Context) {
 	code := ctx.Param("code")
 
 	if len(code) == 0 {
-		argres := model.ServiceDataResult[model.AirportData]{
-			Result: false, 
-			Message: "Ошибка получения шифра. Аргумент 'code' не задан",
-		}
-		ctx.IndentedJSON(500, argres)
+		writeDataError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
 		return
 	}	
 
+	chain, localize, err := responseLanguages(ctx)
+	if err != nil {
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
 	// Call the data method
-	result, err := server.airportService.GetAirportByCode(code)
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.GetAirportByCode(queryCtx, code)
 
 	if err != nil {
-		result = model.ServiceDataResult[model.AirportData]{
-			Result: false, 
-			Message: "Ошибка запроса данных",
-			Validations: &[]model.Validation{
-				{ Message: fmt.Sprintf("Ошибка: %v", err) },
-			},
-		}
-		ctx.IndentedJSON(500, result)
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
+	var body any = result
+	if localize {
+		body = service.LocalizeData(result, func(p model.AirportData) model.LocalizedAirportData {
+			return service.LocalizeAirport(p, chain)
+		})
+	}
+
+	if writeETag(ctx, result.Data.Version, body) {
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, body)	
+}
+
+func (server AppServer) createAirport(ctx *gin.Context) {
+	
+	var input model.AirportInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.CreateAirport(queryCtx, input)
+
+	if err != nil {
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", util.ETag(result.Data.Version))
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) updateAirport(ctx *gin.Context) {
+	
+	var input model.AirportInput
+
+	if err := ctx.ShouldBindJSON(&input); err != nil {
+		writeDataError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	ifMatch, err := server.ifMatch(ctx, input.Code)
+	if err != nil {
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.UpdateAirport(queryCtx, input, ifMatch)
+
+	if err != nil {
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", util.ETag(result.Data.Version))
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+// patchAirport изменяет поля из документа JSON Merge Patch (RFC 7396), тело запроса
+// с типом application/merge-patch+json или application/json
+func (server AppServer) patchAirport(ctx *gin.Context) {
+	
+	code := ctx.Param("code")
+
+	if len(code) == 0 {
+		writeDataError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
+		return
+	}	
+
+	patch, err := ctx.GetRawData()
+	if err != nil {
+		writeDataError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestBody, err))
+		return
+	}
+
+	ifMatch, err := server.ifMatch(ctx, code)
+	if err != nil {
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.PatchAirport(queryCtx, code, patch, ifMatch)
+
+	if err != nil {
+		writeDataError[model.AirportData](ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", util.ETag(result.Data.Version))
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) deleteAirport(ctx *gin.Context) {
+	
+	code := ctx.Param("code")
+
+	if len(code) == 0 {
+		writeDataError[model.AirportData](ctx, domain.NewArgumentError(i18n.MsgRequestCode, nil))
+		return
+	}	
+
+	ifMatch, err := server.ifMatch(ctx, code)
+	if err != nil {
+		writeDataError[string](ctx, err)
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "airports")
+	defer cancel()
+
+	result, err := server.airportService.DeleteAirport(queryCtx, code, ifMatch)
+
+	if err != nil {
+		writeDataError[string](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)	
+}
+
+func (server AppServer) getFlights(ctx *gin.Context) {
+
+	pager := model.PageInfo{
+        Limit:  nil,
+        Offset: nil,
+    }
+
+	var filter model.FlightFilter
+
+	err := ctx.ShouldBindQuery(&pager)
+	if err == nil {
+		err = ctx.ShouldBindQuery(&filter)
+	}
+
+	if err != nil {
+		writeListError[model.FlightData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "flights")
+	defer cancel()
+
+	result, err := server.flightService.GetFlights(queryCtx, filter, pager)
+
+	if err != nil {
+		writeListError[model.FlightData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)
+}
+
+func (server AppServer) getFlightById(ctx *gin.Context) {
+	
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+
+	if err != nil {
+		writeDataError[model.FlightData](ctx, domain.NewArgumentError(i18n.MsgRequestId, nil))
+		return
+	}	
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "flights")
+	defer cancel()
+
+	result, err := server.flightService.GetFlightById(queryCtx, id)
+
+	if err != nil {
+		writeDataError[model.FlightData](ctx, err)
 		return
 	}
 
 	ctx.IndentedJSON(http.StatusOK, result)	
 }
 
+func (server AppServer) search(ctx *gin.Context) {
+
+	var input model.SearchInput
+
+	err := ctx.ShouldBindQuery(&input)
+	if err != nil {
+		writeListError[model.SearchItemData](ctx, domain.NewArgumentError(i18n.MsgRequestArguments, err))
+		return
+	}
+
+	// Call the data method
+	queryCtx, cancel := server.queryContext(ctx, "search")
+	defer cancel()
+
+	result, err := server.searchService.Search(queryCtx, input)
+
+	if err != nil {
+		writeListError[model.SearchItemData](ctx, err)
+		return
+	}
+
+	ctx.IndentedJSON(http.StatusOK, result)
+}
+
+// queryContext возвращает контекст запроса клиента с таймаутом, настроенным для группы методов API.
+// Отключение клиента или истечение таймаута прерывает запросы к базе данных.
+func (server AppServer) queryContext(ctx *gin.Context, endpoint string) (context.Context, context.CancelFunc) {
+	timeout, err := server.config.GetQueryTimeout(endpoint)
+	if err != nil || timeout <= 0 {
+		return context.WithCancel(ctx.Request.Context())
+	}
+	return context.WithTimeout(ctx.Request.Context(), timeout)
+}
+
+// ifMatch возвращает условие If-Match для изменения объекта code: ETag ответа чтения или изменения объекта.
+// Если заголовок обязателен по конфигурации server.require_if_match и не задан - ошибку 428.
+func (server AppServer) ifMatch(ctx *gin.Context, code string) (string, error) {
+	ifMatch := ctx.GetHeader("If-Match")
+	if ifMatch == "" && server.requireIfMatch {
+		return "", domain.NewPreconditionRequiredError(i18n.MsgRequestIfMatch, code)
+	}
+	return ifMatch, nil
+}
 
+// writeETag устанавливает слабый заголовок ETag представления body объекта версии version.
+// Тег меняется вместе с телом ответа, включая места, последние рейсы и язык названий, и принимается в If-Match.
+// Если представление совпадает с If-None-Match, отправляет 304 без тела и возвращает true.
+func writeETag(ctx *gin.Context, version string, body any) bool {
+	representation := util.Version(body)
+	ctx.Header("ETag", util.RepresentationETag(version, representation))
+	if util.MatchETag(ctx.GetHeader("If-None-Match"), version+"."+representation, true) {
+		ctx.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
 
+// errorStatus сопоставляет вид ошибки сервиса с кодом состояния HTTP
+func errorStatus(kind domain.ErrorKind) int {
+	switch kind {
+	case domain.ErrorArgument:
+		return http.StatusBadRequest
+	case domain.ErrorNotFound:
+		return http.StatusNotFound
+	case domain.ErrorConflict, domain.ErrorReference:
+		return http.StatusConflict
+	case domain.ErrorValidation:
+		return http.StatusUnprocessableEntity
+	case domain.ErrorUnavailable:
+		return http.StatusServiceUnavailable
+	case domain.ErrorTimeout:
+		return http.StatusGatewayTimeout
+	case domain.ErrorPrecondition:
+		return http.StatusPreconditionFailed
+	case domain.ErrorPreconditionRequired:
+		return http.StatusPreconditionRequired
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorValidations возвращает сообщения валидации ошибки сервиса на языке цепочки chain
+func errorValidations(serr *domain.ServiceError, chain []string) *[]model.Validation {
+	if len(serr.Validations) > 0 {
+		validations := i18n.LocalizeValidations(serr.Validations, chain)
+		return &validations
+	}
+	if serr.Err != nil {
+		validation := i18n.Validation("", i18n.MsgErrorDetail, serr.Err)
+		if _, _, ok := i18n.KeyOf(serr.Err); ok {
+			validation = i18n.ValidationOf("", serr.Err)
+		}
+		validations := i18n.LocalizeValidations([]model.Validation{validation}, chain)
+		return &validations
+	}
+	return nil
+}
+
+// messageLanguages возвращает цепочку языков сообщений из параметра lang или заголовка Accept-Language.
+// Неверный параметр lang не мешает отправить ошибку: он пропускается.
+// Без предпочтений сообщения выводятся на языке по умолчанию каталога.
+func messageLanguages(ctx *gin.Context) []string {
+	ctx.Header("Vary", "Accept-Language")
+
+	preferred, err := util.ParseLangParam(ctx.Query("lang"))
+	if err != nil || preferred == nil {
+		preferred = util.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	}
+	if preferred == nil || slices.Contains(preferred, util.LangAll) {
+		return nil
+	}
+	return util.LanguageChain(preferred)
+}
+
+// Ответ с ошибкой сервиса: код состояния HTTP и сообщения на языке запроса
+type errorResponse struct {
+	status      int
+	message     string
+	code        string
+	validations *[]model.Validation
+}
+
+// newErrorResponse сопоставляет ошибку сервиса с кодом состояния HTTP и переводит сообщения.
+// Сопоставление общее для всех методов API и форматов ответа.
+func newErrorResponse(ctx *gin.Context, err error) errorResponse {
+	serr := domain.AsServiceError(err)
+	chain := messageLanguages(ctx)
+	ctx.Writer.Header().Add("Vary", "Accept")
+	return errorResponse{
+		status:      errorStatus(serr.Kind),
+		message:     i18n.Text(chain, serr.Key, serr.Args...),
+		code:        serr.Key,
+		validations: errorValidations(serr, chain),
+	}
+}
+
+// Тип содержимого ошибки RFC 7807
+const problemContentType = "application/problem+json"
+
+// writeProblem отправляет ошибку в формате RFC 7807, если клиент запросил application/problem+json в заголовке Accept.
+// Возвращает false, если клиент ожидает ошибку в виде результата сервиса.
+func writeProblem(ctx *gin.Context, response errorResponse) bool {
+	if !strings.Contains(ctx.GetHeader("Accept"), problemContentType) {
+		return false
+	}
+
+	problem := model.ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(response.status),
+		Status:   response.status,
+		Detail:   response.message,
+		Instance: ctx.Request.URL.Path,
+		Code:     response.code,
+	}
+	if response.validations != nil {
+		problem.Errors = util.Map(*response.validations, func(p model.Validation) model.ProblemField {
+			return model.ProblemField{Property: p.Property, Message: p.Message, Code: p.Code}
+		})
+	}
+
+	ctx.Header("Content-Type", problemContentType)
+	ctx.IndentedJSON(response.status, problem)
+	return true
+}
+
+// writeDataError отправляет ошибку сервиса в виде ServiceDataResult или RFC 7807.
+// Сообщения выводятся на языке запроса, ключ сообщения передается в поле Code.
+func writeDataError[TD any](ctx *gin.Context, err error) {
+	response := newErrorResponse(ctx, err)
+	if writeProblem(ctx, response) {
+		return
+	}
+
+	result := model.ServiceDataResult[TD]{
+		Result: false, 
+		Message: response.message,
+		Validations: response.validations,
+		Code: &response.code,
+	}
+	ctx.IndentedJSON(response.status, result)
+}
+
+// Форматы ответа списка
+const (
+	formatJSON    = "json"
+	formatGeoJSON = "geojson"
+)
+
+// responseFormat возвращает формат ответа из параметра format или заголовка Accept,
+// если формат не задан - формат метода по умолчанию
+func responseFormat(ctx *gin.Context, fallback string) (string, error) {
+	var input model.FormatInput
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		return "", domain.NewArgumentError(i18n.MsgRequestArguments, err)
+	}
+
+	switch input.Format {
+	case formatJSON, formatGeoJSON:
+		return input.Format, nil
+	case "":
+	default:
+		return "", domain.NewArgumentError(i18n.MsgRequestFormat, nil, input.Format)
+	}
+
+	if strings.Contains(ctx.GetHeader("Accept"), geo.GeoJSONContentType) {
+		return formatGeoJSON, nil
+	}
+	return fallback, nil
+}
+
+// writeGeoJSON записывает коллекцию объектов GeoJSON с типом содержимого application/geo+json
+func writeGeoJSON(ctx *gin.Context, collection geo.FeatureCollection) {
+	ctx.Header("Content-Type", geo.GeoJSONContentType)
+	ctx.IndentedJSON(http.StatusOK, collection)
+}
+
+// responseLanguages возвращает цепочку языков ответа из параметра lang или заголовка Accept-Language.
+// Если язык не задан или задан lang=all, ответ содержит названия на всех языках (localize = false).
+func responseLanguages(ctx *gin.Context) (chain []string, localize bool, err error) {
+	ctx.Header("Vary", "Accept-Language")
+
+	var input model.LangInput
+	if err := ctx.ShouldBindQuery(&input); err != nil {
+		return nil, false, domain.NewArgumentError(i18n.MsgRequestArguments, err)
+	}
+
+	preferred, err := util.ParseLangParam(input.Lang)
+	if err != nil {
+		key, args, _ := i18n.KeyOf(err)
+		return nil, false, domain.NewArgumentError(key, nil, args...)
+	}
+	if preferred == nil {
+		preferred = util.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))
+	}
+	if preferred == nil || slices.Contains(preferred, util.LangAll) {
+		return nil, false, nil
+	}
+
+	return util.LanguageChain(preferred), true, nil
+}
+
+// writeListError отправляет ошибку сервиса в виде ServiceListResult или RFC 7807
+func writeListError[TD any](ctx *gin.Context, err error) {
+	response := newErrorResponse(ctx, err)
+	if writeProblem(ctx, response) {
+		return
+	}
+
+	result := model.ServiceListResult[TD]{
+		Result: false, 
+		Message: response.message,
+		Validations: response.validations,
+		Code: &response.code,
+	}
+	ctx.IndentedJSON(response.status, result)
+}
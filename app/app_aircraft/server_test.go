@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/snpavlov/app_aircraft/internal/conf"
+	"github.com/snpavlov/app_aircraft/internal/model"
+	"github.com/snpavlov/app_aircraft/internal/repo"
+	"github.com/snpavlov/app_aircraft/internal/service"
+)
+
+// Конфигурация без таймаутов запросов
+type configStub struct {
+	conf.IConfiguration
+}
+
+func (configStub) GetQueryTimeout(endpoint string) (time.Duration, error) {
+	return 0, nil
+}
+
+// Единица работы, вызывающая действия без транзакции
+type unitOfWorkStub struct{}
+
+func (unitOfWorkStub) Do(ctx context.Context, fn func(tx repo.DBTX) error) error {
+	return fn(nil)
+}
+
+// Репозиторий одного самолета в памяти
+type aircraftRepoStub struct {
+	repo.IAircraftRepo
+	aircraft *model.AircraftData
+}
+
+func (stub aircraftRepoStub) GetDBConnection() (*sql.DB, error) {
+	return nil, nil
+}
+
+func (stub aircraftRepoStub) UnitOfWork() (repo.IUnitOfWork[repo.DBTX], error) {
+	return unitOfWorkStub{}, nil
+}
+
+func (stub aircraftRepoStub) get(code string) *model.AircraftData {
+	if code != stub.aircraft.Code {
+		return nil
+	}
+	item := *stub.aircraft
+	return &item
+}
+
+func (stub aircraftRepoStub) GetAircraftItemByCodeAsync(ctx context.Context, db *sql.DB, code string) (*model.AircraftData, error) {
+	return stub.get(code), nil
+}
+
+func (stub aircraftRepoStub) GetAircraftForUpdate(ctx context.Context, db repo.DBTX, code string) (*model.AircraftData, error) {
+	return stub.get(code), nil
+}
+
+func (stub aircraftRepoStub) GetExistsByCode(ctx context.Context, db repo.DBTX, code string) (bool, error) {
+	return stub.get(code) != nil, nil
+}
+
+func (stub aircraftRepoStub) UpdateAircraft(ctx context.Context, db repo.DBTX, input model.AircraftInput) (*model.AircraftData, error) {
+	return stub.ReplaceAircraft(ctx, db, input)
+}
+
+func (stub aircraftRepoStub) ReplaceAircraft(ctx context.Context, db repo.DBTX, input model.AircraftInput) (*model.AircraftData, error) {
+	stub.aircraft.NameRu, stub.aircraft.NameEn, stub.aircraft.Names = input.NameRu, input.NameEn, input.Names
+	stub.aircraft.Range = input.Range
+	return stub.get(input.Code), nil
+}
+
+// TestServer_AircraftETag тестирует, что ETag ответа чтения принимается в If-Match изменения,
+// а после изменения устаревает
+func TestServer_AircraftETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	update := `{"code": "773", "nameRu": "Боинг 777-300", "nameEn": "Boeing 777-300", "range": 11200}`
+	patch := `{"range": 11300}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+	}{
+		{"Update", http.MethodPost, "/api/v1/aircrafts/update", update},
+		{"Patch", http.MethodPatch, "/api/v1/aircrafts/773", patch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aircraft := &model.AircraftData{Code: "773", NameRu: "Боинг 777-300", NameEn: "Boeing 777-300",
+				Range: 11100, SeatCount: 402}
+			server := AppServer{config: configStub{}, requireIfMatch: true,
+				aircraftService: service.AircraftService{Repo: aircraftRepoStub{aircraft: aircraft}}}
+
+			router := gin.New()
+			router.GET("/api/v1/aircrafts/:code", server.getAircaftByCode)
+			router.POST("/api/v1/aircrafts/update", server.updateAircraft)
+			router.PATCH("/api/v1/aircrafts/:code", server.patchAircraft)
+
+			send := func(method string, path string, body string, headers map[string]string) *httptest.ResponseRecorder {
+				request := httptest.NewRequest(method, path, strings.NewReader(body))
+				for name, value := range headers {
+					request.Header.Set(name, value)
+				}
+				response := httptest.NewRecorder()
+				router.ServeHTTP(response, request)
+				return response
+			}
+
+			read := send(http.MethodGet, "/api/v1/aircrafts/773", "", nil)
+			etag := read.Header().Get("ETag")
+			if read.Code != http.StatusOK || etag == "" {
+				t.Fatalf("Чтение: ожидался ответ 200 с ETag, получено %d, ETag '%s'", read.Code, etag)
+			}
+
+			if cached := send(http.MethodGet, "/api/v1/aircrafts/773", "", map[string]string{"If-None-Match": etag}); cached.Code != http.StatusNotModified {
+				t.Errorf("Повторное чтение: ожидался ответ 304, получено %d", cached.Code)
+			}
+
+			written := send(tt.method, tt.path, tt.body, map[string]string{"If-Match": etag})
+			if written.Code != http.StatusOK {
+				t.Fatalf("Изменение с ETag чтения: ожидался ответ 200, получено %d: %s", written.Code, written.Body)
+			}
+
+			if stale := send(tt.method, tt.path, tt.body, map[string]string{"If-Match": etag}); stale.Code != http.StatusPreconditionFailed {
+				t.Errorf("Изменение с устаревшим ETag: ожидался ответ 412, получено %d", stale.Code)
+			}
+
+			if cached := send(http.MethodGet, "/api/v1/aircrafts/773", "", map[string]string{"If-None-Match": etag}); cached.Code != http.StatusOK {
+				t.Errorf("Чтение после изменения: ожидался ответ 200, получено %d", cached.Code)
+			}
+		})
+	}
+}
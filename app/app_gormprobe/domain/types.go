@@ -1,40 +1,61 @@
 package domain
 
 import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
-	"strings"
+	"math"
 	"strconv"
-	"database/sql/driver"
+	"strings"
 )
 
+// Point is a PostgreSQL point: X holds the longitude and Y the latitude
 type Point struct {
 	X float64
 	Y float64
 }
 
+// JSON representation of a Point
+type pointJSON struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
 // Implement the Valuer interface for saving to the database
 func (p Point) Value() (driver.Value, error) {
-	return fmt.Sprintf("(%f,%f)", p.X, p.Y), nil
+	return "(" + formatCoordinate(p.X) + "," + formatCoordinate(p.Y) + ")", nil
 }
 
-// Implement the Scanner interface for reading from the database
+// Implement the Scanner interface for reading from the database.
+// NULL leaves the zero Point.
 func (p *Point) Scan(value interface{}) error {
-	s, ok := value.(string)
-	if !ok {
+	var s string
+
+	switch v := value.(type) {
+	case nil:
+		*p = Point{}
+		return nil
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
 		return fmt.Errorf("could not scan type %T into Point", value)
 	}
 
-	s = strings.Trim(s, "()")
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
 	parts := strings.Split(s, ",")
 	if len(parts) != 2 {
 		return fmt.Errorf("invalid point format: %s", s)
 	}
 
-	x, err := strconv.ParseFloat(parts[0], 64)
+	x, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
 	if err != nil {
 		return fmt.Errorf("invalid X coordinate: %w", err)
 	}
-	y, err := strconv.ParseFloat(parts[1], 64)
+	y, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
 	if err != nil {
 		return fmt.Errorf("invalid Y coordinate: %w", err)
 	}
@@ -43,3 +64,46 @@ func (p *Point) Scan(value interface{}) error {
 	p.Y = y
 	return nil
 }
+
+// Implement the json.Marshaler interface as {"lat": Y, "lon": X}
+func (p Point) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pointJSON{Lat: p.Y, Lon: p.X})
+}
+
+// Implement the json.Unmarshaler interface, both lat and lon are required
+func (p *Point) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil
+	}
+
+	var v struct {
+		Lat *float64 `json:"lat"`
+		Lon *float64 `json:"lon"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid point: %w", err)
+	}
+	if v.Lat == nil || v.Lon == nil {
+		return fmt.Errorf("invalid point: both lat and lon are required")
+	}
+
+	p.X = *v.Lon
+	p.Y = *v.Lat
+	return nil
+}
+
+// Validate checks that the latitude is in [-90, 90] and the longitude in [-180, 180]
+func (p Point) Validate() error {
+	if math.IsNaN(p.Y) || p.Y < -90 || p.Y > 90 {
+		return fmt.Errorf("latitude %v is out of range [-90, 90]", p.Y)
+	}
+	if math.IsNaN(p.X) || p.X < -180 || p.X > 180 {
+		return fmt.Errorf("longitude %v is out of range [-180, 180]", p.X)
+	}
+	return nil
+}
+
+// formatCoordinate formats a coordinate with the shortest exact representation
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}